@@ -0,0 +1,49 @@
+package slog
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// replaceAllowlist returns a ReplaceAttr function that drops every attribute
+// not in the config's allowlist, or nil if no allowlist is configured.
+//
+// The built-in time, level, message and source attributes always pass.
+// An attribute inside groups passes if its dotted path (for example "req.id")
+// or the path of any enclosing group (for example "req") is allowed, so
+// allowing a group name keeps the whole group. Groups left without any
+// allowed attribute are omitted by the handlers.
+func replaceAllowlist(config *Config) func([]string, slog.Attr) slog.Attr {
+	if len(config.AttrAllowlist) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(config.AttrAllowlist))
+	for _, key := range config.AttrAllowlist {
+		allowed[key] = true
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && isBuiltinKey(a.Key) {
+			return a
+		}
+		for i := 1; i <= len(groups); i++ {
+			if allowed[strings.Join(groups[:i], ".")] {
+				return a
+			}
+		}
+		if allowed[strings.Join(append(groups[:len(groups):len(groups)], a.Key), ".")] {
+			return a
+		}
+		return slog.Attr{}
+	}
+}
+
+// isBuiltinKey reports whether key is the key of a built-in attribute.
+func isBuiltinKey(key string) bool {
+	switch key {
+	case slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey:
+		return true
+	}
+	return false
+}
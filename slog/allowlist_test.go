@@ -0,0 +1,70 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestReplaceAllowlist(t *testing.T) {
+	var buf bytes.Buffer
+	config := &Config{HandlerType: "json", AttrAllowlist: []string{"keep", "req.id", "http"}}
+	logger := slog.New(createHandler(config, &buf))
+
+	logger.Info("test message",
+		"keep", 1,
+		"drop", 2,
+		slog.Group("req", "id", "r-1", "secret", "s"),
+		slog.Group("http", "method", "GET", slog.Group("header", "host", "example.com")),
+		slog.Group("other", "id", "o-1"),
+	)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	delete(record, slog.TimeKey)
+
+	expected := map[string]any{
+		slog.LevelKey:   "INFO",
+		slog.MessageKey: "test message",
+		"keep":          float64(1),
+		"req":           map[string]any{"id": "r-1"},
+		"http":          map[string]any{"method": "GET", "header": map[string]any{"host": "example.com"}},
+	}
+	if !reflect.DeepEqual(record, expected) {
+		t.Errorf("expected %v, got %v", expected, record)
+	}
+}
+
+func TestReplaceAllowlistWithGroup(t *testing.T) {
+	var buf bytes.Buffer
+	config := &Config{HandlerType: "json", AttrAllowlist: []string{"req.id"}}
+	logger := slog.New(createHandler(config, &buf)).WithGroup("req")
+
+	logger.Info("test message", "id", "r-1", "secret", "s")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	if !reflect.DeepEqual(record["req"], map[string]any{"id": "r-1"}) {
+		t.Errorf("expected only req.id to pass, got %v", record["req"])
+	}
+}
+
+func TestReadConfigAttrAllowlist(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerAttrAllowlist: "user_id, req.id,,"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"user_id", "req.id"}; !reflect.DeepEqual(config.AttrAllowlist, expected) {
+		t.Errorf("expected %v, got %v", expected, config.AttrAllowlist)
+	}
+}
@@ -0,0 +1,152 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// parseAuditOutput parses LOGGER_AUDIT_OUTPUT, a single output in the syntax
+// of LOGGER_OUTPUTS.
+func parseAuditOutput(s string) (Output, error) {
+	outputs, err := parseOutputs(s)
+	if err != nil {
+		return Output{}, fmt.Errorf("%w: %w", ErrInvalidAudit, err)
+	}
+	if len(outputs) != 1 {
+		return Output{}, fmt.Errorf("%w: want a single output, got %q", ErrInvalidAudit, s)
+	}
+	return outputs[0], nil
+}
+
+// buildAuditHandler creates the format handler and writer of the config's
+// audit output. It shares all settings other than the handler type and writer
+// with config.
+func buildAuditHandler(config *Config) (slog.Handler, error) {
+	c := *config
+	c.HandlerType = config.AuditOutput.HandlerType
+	c.WriterType = config.AuditOutput.WriterType
+	c.WriterFilePath = config.AuditOutput.WriterFilePath
+	c.Outputs = nil
+
+	writer, err := buildWriter(&c)
+	if err != nil {
+		return nil, err
+	}
+	return createFormatHandler(&c, writer), nil
+}
+
+// isAuditMarker reports whether v marks a record for auditing: a true bool,
+// or a string such as "true" or "1" that strconv.ParseBool accepts as true.
+func isAuditMarker(v slog.Value) bool {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindBool:
+		return v.Bool()
+	case slog.KindString:
+		marked, err := strconv.ParseBool(v.String())
+		return err == nil && marked
+	default:
+		return false
+	}
+}
+
+// auditHandler is a wrapper handler that also writes the records carrying a
+// true marker attribute to the audit handler, regardless of where next writes
+// them. The marker is an attribute with the marker key, logged with the record
+// or added with WithAttrs. If strip is set, marker attributes are removed from
+// the records passed to next; the audit copy keeps them.
+type auditHandler struct {
+	next   slog.Handler
+	audit  slog.Handler
+	key    string
+	strip  bool
+	marked bool
+}
+
+// newAuditHandler creates a new handler that copies the records marked with
+// key to audit.
+func newAuditHandler(next, audit slog.Handler, key string, strip bool) slog.Handler {
+	return &auditHandler{next: next, audit: audit, key: key, strip: strip}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *auditHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *auditHandler) Handle(ctx context.Context, r slog.Record) error {
+	marked, hasKey := h.marked, false
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == h.key {
+			hasKey = true
+			marked = marked || isAuditMarker(a.Value)
+		}
+		return true
+	})
+
+	main := r
+	if h.strip && hasKey {
+		main = slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+		r.Attrs(func(a slog.Attr) bool {
+			if a.Key != h.key {
+				main.AddAttrs(a)
+			}
+			return true
+		})
+	} else if marked {
+		main = r.Clone()
+	}
+	if !marked {
+		return h.next.Handle(ctx, main)
+	}
+
+	err := h.next.Handle(ctx, main)
+	return errors.Join(err, h.audit.Handle(ctx, r))
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *auditHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	marked := h.marked
+	kept := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if a.Key == h.key {
+			marked = marked || isAuditMarker(a.Value)
+			if h.strip {
+				continue
+			}
+		}
+		kept = append(kept, a)
+	}
+	return &auditHandler{
+		next:   h.next.WithAttrs(kept),
+		audit:  h.audit.WithAttrs(attrs),
+		key:    h.key,
+		strip:  h.strip,
+		marked: marked,
+	}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *auditHandler) WithGroup(name string) slog.Handler {
+	return &auditHandler{
+		next:   h.next.WithGroup(name),
+		audit:  h.audit.WithGroup(name),
+		key:    h.key,
+		strip:  h.strip,
+		marked: h.marked,
+	}
+}
+
+// describe implements describer.
+func (h *auditHandler) describe() string {
+	return "audit(" + h.key + ")"
+}
+
+// unwrap implements describer.
+func (h *auditHandler) unwrap() slog.Handler {
+	return h.next
+}
@@ -0,0 +1,125 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		strip         bool
+		expectedMain  string
+		expectedAudit string
+	}{
+		{
+			name:          "keep marker",
+			expectedMain:  "level=INFO msg=login user=a audit=true\nlevel=INFO msg=browse user=a audit=false\nlevel=INFO msg=browse user=a\nlevel=INFO msg=grant user=a audit=1\n",
+			expectedAudit: "level=INFO msg=login user=a audit=true\nlevel=INFO msg=grant user=a audit=1\n",
+		},
+		{
+			name:          "strip marker",
+			strip:         true,
+			expectedMain:  "level=INFO msg=login user=a\nlevel=INFO msg=browse user=a\nlevel=INFO msg=browse user=a\nlevel=INFO msg=grant user=a\n",
+			expectedAudit: "level=INFO msg=login user=a audit=true\nlevel=INFO msg=grant user=a audit=1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var main, audit bytes.Buffer
+			opts := &slog.HandlerOptions{ReplaceAttr: removeTime}
+			handler := newAuditHandler(slog.NewTextHandler(&main, opts), slog.NewTextHandler(&audit, opts), "audit", tt.strip)
+			logger := slog.New(handler).With("user", "a")
+
+			logger.Info("login", "audit", true)
+			logger.Info("browse", "audit", false)
+			logger.Info("browse")
+			logger.With("audit", "1").Info("grant")
+
+			if main.String() != tt.expectedMain {
+				t.Errorf("expected main output %q, got %q", tt.expectedMain, main.String())
+			}
+			if audit.String() != tt.expectedAudit {
+				t.Errorf("expected audit output %q, got %q", tt.expectedAudit, audit.String())
+			}
+		})
+	}
+}
+
+func TestAuditOutput(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	dir := t.TempDir()
+	appPath := filepath.Join(dir, "app.log")
+	auditPath := filepath.Join(dir, "audit.log")
+	clearEnvVars()
+	os.Setenv(EnvLoggerWriter, "file")
+	os.Setenv(EnvLoggerWriterFilePath, appPath)
+	os.Setenv(EnvLoggerAuditMarkerKey, "audit")
+	os.Setenv(EnvLoggerAuditOutput, "json=file:"+auditPath)
+	os.Setenv(EnvLoggerAuditStripMarker, "true")
+
+	logger, err := Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("role granted", "audit", true, "role", "admin")
+	logger.Info("page viewed")
+
+	if err := Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	appData, err := os.ReadFile(appPath)
+	if err != nil {
+		t.Fatalf("failed to read app output: %v", err)
+	}
+	auditData, err := os.ReadFile(auditPath)
+	if err != nil {
+		t.Fatalf("failed to read audit output: %v", err)
+	}
+	if strings.Count(string(appData), "\n") != 2 || strings.Contains(string(appData), "audit") {
+		t.Errorf("expected both records without the marker in the app output, got %q", appData)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(auditData)), "\n"); len(lines) != 1 ||
+		!strings.Contains(lines[0], `"msg":"role granted","audit":true,"role":"admin"`) {
+		t.Errorf("expected the marked record in the audit output, got %q", auditData)
+	}
+}
+
+func TestReadConfigAudit(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{
+		EnvLoggerAuditMarkerKey: "audit",
+		EnvLoggerAuditOutput:    "json=stdout",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.AuditMarkerKey != "audit" || config.AuditOutput != (Output{HandlerType: "json", WriterType: "stdout"}) || config.AuditStripMarker {
+		t.Errorf("unexpected audit settings %q %+v %v", config.AuditMarkerKey, config.AuditOutput, config.AuditStripMarker)
+	}
+
+	for _, envVars := range []map[string]string{
+		{EnvLoggerAuditMarkerKey: "audit"},
+		{EnvLoggerAuditOutput: "json=stdout"},
+		{EnvLoggerAuditMarkerKey: "audit", EnvLoggerAuditOutput: "json=stdout,text=stderr"},
+		{EnvLoggerAuditMarkerKey: "audit", EnvLoggerAuditOutput: "json=file"},
+	} {
+		if _, err := readConfigFromEnv(t, envVars); !errors.Is(err, ErrInvalidAudit) {
+			t.Errorf("%v: expected ErrInvalidAudit, got %v", envVars, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package slog
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultWriterBreakerCooldown is the time the writer circuit breaker stays
+// open when no cooldown is configured.
+const DefaultWriterBreakerCooldown = 30 * time.Second
+
+// breakerWriter is a circuit breaker around a writer. After threshold
+// consecutive write failures, it stops attempting the real writer for the
+// cooldown period and diverts writes to the fallback writer. Once the cooldown
+// has elapsed, the next write probes the real writer: success closes the
+// breaker, failure opens it for another cooldown period.
+type breakerWriter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	fallback  io.Writer
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	failures  int
+	openUntil time.Time // zero while the breaker is closed
+}
+
+// newBreakerWriter creates a circuit breaker around w that diverts to fallback.
+func newBreakerWriter(w, fallback io.Writer, threshold int, cooldown time.Duration) *breakerWriter {
+	return &breakerWriter{
+		w:         w,
+		fallback:  fallback,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Write implements io.Writer.
+func (b *breakerWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	open := !b.openUntil.IsZero()
+	if open && b.now().Before(b.openUntil) {
+		return b.fallback.Write(p)
+	}
+
+	n, err := b.w.Write(p)
+	if err == nil {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		return n, nil
+	}
+
+	b.failures++
+	if open || b.failures >= b.threshold {
+		// Either the probe after the cooldown failed or the threshold was
+		// reached: (re)open the breaker and divert this write as well.
+		b.openUntil = b.now().Add(b.cooldown)
+		return b.fallback.Write(p)
+	}
+	return n, err
+}
@@ -0,0 +1,144 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// toggleWriter is a writer whose writes fail while fail is set.
+type toggleWriter struct {
+	fail   bool
+	writes int
+	buf    bytes.Buffer
+}
+
+func (w *toggleWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.fail {
+		return 0, errors.New("write failed")
+	}
+	return w.buf.Write(p)
+}
+
+func TestBreakerWriter(t *testing.T) {
+	real := &toggleWriter{fail: true}
+	var fallback bytes.Buffer
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	breaker := newBreakerWriter(real, &fallback, 3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	// Failures below the threshold are returned to the caller
+	for i := range 2 {
+		if _, err := breaker.Write([]byte("x")); err == nil {
+			t.Fatalf("write %d: expected error below threshold", i)
+		}
+	}
+	if fallback.Len() != 0 {
+		t.Errorf("expected no fallback writes below threshold, got %q", fallback.String())
+	}
+
+	// Reaching the threshold opens the breaker and diverts the write
+	if _, err := breaker.Write([]byte("a")); err != nil {
+		t.Errorf("unexpected error when opening breaker: %v", err)
+	}
+	// While open, the real writer is not attempted
+	if _, err := breaker.Write([]byte("b")); err != nil {
+		t.Errorf("unexpected error while open: %v", err)
+	}
+	if real.writes != 3 {
+		t.Errorf("expected 3 attempts on the real writer, got %d", real.writes)
+	}
+	if fallback.String() != "ab" {
+		t.Errorf("expected fallback to receive %q, got %q", "ab", fallback.String())
+	}
+
+	// After the cooldown a failing probe reopens the breaker
+	now = now.Add(time.Minute)
+	breaker.Write([]byte("c"))
+	if real.writes != 4 {
+		t.Errorf("expected a probe write after cooldown, got %d attempts", real.writes)
+	}
+	breaker.Write([]byte("d"))
+	if real.writes != 4 {
+		t.Errorf("expected breaker to reopen after failed probe, got %d attempts", real.writes)
+	}
+
+	// After the next cooldown a successful probe closes the breaker
+	now = now.Add(time.Minute)
+	real.fail = false
+	if _, err := breaker.Write([]byte("e")); err != nil {
+		t.Errorf("unexpected error on successful probe: %v", err)
+	}
+	if _, err := breaker.Write([]byte("f")); err != nil {
+		t.Errorf("unexpected error after recovery: %v", err)
+	}
+	if real.buf.String() != "ef" {
+		t.Errorf("expected real writer to receive %q after recovery, got %q", "ef", real.buf.String())
+	}
+	if fallback.String() != "abcd" {
+		t.Errorf("expected fallback to receive %q, got %q", "abcd", fallback.String())
+	}
+}
+
+func TestReadConfigWriterBreaker(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	tests := []struct {
+		name              string
+		envVars           map[string]string
+		expectedThreshold int
+		expectedCooldown  time.Duration
+		expectedError     error
+	}{
+		{
+			name:              "Default Cooldown",
+			envVars:           map[string]string{EnvLoggerWriterBreakerThreshold: "5"},
+			expectedThreshold: 5,
+			expectedCooldown:  DefaultWriterBreakerCooldown,
+		},
+		{
+			name: "Custom Cooldown",
+			envVars: map[string]string{
+				EnvLoggerWriterBreakerThreshold: "5",
+				EnvLoggerWriterBreakerCooldown:  "10s",
+			},
+			expectedThreshold: 5,
+			expectedCooldown:  10 * time.Second,
+		},
+		{
+			name:          "Invalid Threshold",
+			envVars:       map[string]string{EnvLoggerWriterBreakerThreshold: "many"},
+			expectedError: ErrInvalidWriterBreaker,
+		},
+		{
+			name:          "Invalid Cooldown",
+			envVars:       map[string]string{EnvLoggerWriterBreakerCooldown: "soon"},
+			expectedError: ErrInvalidWriterBreaker,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := readConfigFromEnv(t, tt.envVars)
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error to be '%v' but got '%v'", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.WriterBreakerThreshold != tt.expectedThreshold {
+				t.Errorf("WriterBreakerThreshold: expected %v, got %v", tt.expectedThreshold, config.WriterBreakerThreshold)
+			}
+			if config.WriterBreakerCooldown != tt.expectedCooldown {
+				t.Errorf("WriterBreakerCooldown: expected %v, got %v", tt.expectedCooldown, config.WriterBreakerCooldown)
+			}
+		})
+	}
+}
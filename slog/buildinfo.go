@@ -0,0 +1,44 @@
+package slog
+
+import (
+	"log/slog"
+	"sync"
+)
+
+var (
+	buildInfoMu      sync.RWMutex
+	buildInfoVersion string
+	buildInfoCommit  string
+)
+
+// SetBuildInfo sets the application version and commit that loggers built
+// afterwards add to every record as "version" and "commit" attributes. Empty
+// values are omitted. It is meant for values injected at build time:
+//
+//	var version, commit string // set with -ldflags "-X main.version=..."
+//
+//	func main() {
+//		planks_slog.SetBuildInfo(version, commit)
+//		planks_slog.Init()
+//	}
+func SetBuildInfo(version, commit string) {
+	buildInfoMu.Lock()
+	defer buildInfoMu.Unlock()
+	buildInfoVersion = version
+	buildInfoCommit = commit
+}
+
+// buildInfoAttrs returns the attributes set with SetBuildInfo.
+func buildInfoAttrs() []slog.Attr {
+	buildInfoMu.RLock()
+	defer buildInfoMu.RUnlock()
+
+	var attrs []slog.Attr
+	if buildInfoVersion != "" {
+		attrs = append(attrs, slog.String("version", buildInfoVersion))
+	}
+	if buildInfoCommit != "" {
+		attrs = append(attrs, slog.String("commit", buildInfoCommit))
+	}
+	return attrs
+}
@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestSetBuildInfo(t *testing.T) {
+	defer SetBuildInfo("", "")
+
+	var before bytes.Buffer
+	beforeLogger := slog.New(createHandler(&Config{HandlerType: "bare"}, &before))
+
+	SetBuildInfo("1.2.3", "abc123")
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare"}, &buf))
+	logger.Info("test", "k", "v")
+
+	if expected := "msg=test version=1.2.3 commit=abc123 k=v\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	// Loggers built before SetBuildInfo are unaffected
+	beforeLogger.Info("test")
+	if expected := "msg=test\n"; before.String() != expected {
+		t.Errorf("expected %q, got %q", expected, before.String())
+	}
+}
+
+func TestSetBuildInfoOmitsEmpty(t *testing.T) {
+	defer SetBuildInfo("", "")
+
+	SetBuildInfo("1.2.3", "")
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare"}, &buf))
+	logger.Info("test")
+
+	if expected := "msg=test version=1.2.3\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,56 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// causeHandler is a wrapper handler that adds the error and the cancellation
+// cause of a done context to each record logged with it, as "ctx_err" and
+// "ctx_cause" attributes, which shows why an aborted operation was canceled.
+// Records logged with a live context are left as they are.
+type causeHandler struct {
+	next slog.Handler
+}
+
+// newCauseHandler creates a new handler that adds the cancellation cause of
+// done contexts to each record.
+func newCauseHandler(next slog.Handler) slog.Handler {
+	return &causeHandler{next: next}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *causeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *causeHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			r = r.Clone()
+			r.AddAttrs(slog.String("ctx_err", err.Error()), slog.String("ctx_cause", context.Cause(ctx).Error()))
+		}
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *causeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &causeHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *causeHandler) WithGroup(name string) slog.Handler {
+	return &causeHandler{next: h.next.WithGroup(name)}
+}
+
+// describe implements describer.
+func (h *causeHandler) describe() string {
+	return "ctx_cause"
+}
+
+// unwrap implements describer.
+func (h *causeHandler) unwrap() slog.Handler {
+	return h.next
+}
@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCauseHandler(t *testing.T) {
+	canceled, cancel := context.WithCancelCause(context.Background())
+	cancel(errors.New("client went away"))
+
+	expired, cancelExpired := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancelExpired()
+	<-expired.Done()
+
+	live, cancelLive := context.WithCancel(context.Background())
+	defer cancelLive()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{"canceled", canceled, `msg=test k=v ctx_err="context canceled" ctx_cause="client went away"`},
+		{"deadline exceeded", expired, `msg=test k=v ctx_err="context deadline exceeded" ctx_cause="context deadline exceeded"`},
+		{"live", live, `msg=test k=v`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(createHandler(&Config{HandlerType: "bare", AddCtxCause: true}, &buf))
+			logger.InfoContext(tt.ctx, "test", "k", "v")
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReadConfigCtxCause(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerCtxCause: "true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.AddCtxCause {
+		t.Errorf("expected AddCtxCause to be enabled")
+	}
+}
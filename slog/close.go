@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"errors"
+	"sync"
+)
+
+// closePhase orders the cleanups run by Close. Phases run in order; the
+// cleanups of a phase run in reverse order of registration.
+type closePhase int
+
+const (
+	// closeStopTasks stops the background tasks that log or change the
+	// logging setup, so that nothing is logged while the output closes.
+	closeStopTasks closePhase = iota
+	// closeFlush finishes the output buffered or framed by writer wrappers,
+	// while the underlying writers are still open.
+	closeFlush
+	// closeWriters closes the writers, such as log files.
+	closeWriters
+	// closeMarkers removes the files that mark the running process, such as
+	// the PID file, once logging is done.
+	closeMarkers
+
+	numClosePhases
+)
+
+var (
+	closersMu sync.Mutex
+	closers   [numClosePhases][]func() error
+)
+
+// registerCloser registers a cleanup function to be run by Close in the given phase.
+func registerCloser(phase closePhase, fn func() error) {
+	closersMu.Lock()
+	defer closersMu.Unlock()
+	closers[phase] = append(closers[phase], fn)
+}
+
+// Close releases the resources the package set up while building loggers,
+// in order: it stops the heartbeat started for LOGGER_HEARTBEAT_INTERVAL and
+// the level provider, closes the arrays of LOGGER_JSON_ARRAY, closes the log
+// files opened for file writers and finally removes the PID file written for
+// LOGGER_PIDFILE. Within each of these steps, cleanups run in reverse order
+// of registration. Each cleanup runs at most once, and every cleanup runs even
+// if an earlier one fails; errors are joined.
+// Call Close when the process is done logging; loggers writing to files must
+// not be used after Close.
+func Close() error {
+	closersMu.Lock()
+	phases := closers
+	closers = [numClosePhases][]func() error{}
+	closersMu.Unlock()
+
+	var errs []error
+	for _, fns := range phases {
+		for i := len(fns) - 1; i >= 0; i-- {
+			if err := fns[i](); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
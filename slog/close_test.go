@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestClose(t *testing.T) {
+	var order []int
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	registerCloser(closeWriters, func() error { order = append(order, 1); return errFirst })
+	registerCloser(closeWriters, func() error { order = append(order, 2); return errSecond })
+	registerCloser(closeWriters, func() error { order = append(order, 3); return nil })
+
+	err := Close()
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("expected both errors to be joined, got %v", err)
+	}
+	if expected := []int{3, 2, 1}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected cleanups in order %v, got %v", expected, order)
+	}
+
+	// Cleanups run only once
+	if err := Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(order) != 3 {
+		t.Errorf("expected no further cleanups, got %v", order)
+	}
+}
+
+func TestClosePhases(t *testing.T) {
+	var order []string
+	record := func(name string) func() error {
+		return func() error {
+			order = append(order, name)
+			return nil
+		}
+	}
+	// Registered in the order the features are set up by Init.
+	registerCloser(closeWriters, record("file"))
+	registerCloser(closeFlush, record("json array"))
+	registerCloser(closeMarkers, record("pidfile"))
+	registerCloser(closeStopTasks, record("heartbeat"))
+	registerCloser(closeWriters, record("second file"))
+	registerCloser(closeStopTasks, record("level provider"))
+
+	if err := Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"level provider", "heartbeat", "json array", "second file", "file", "pidfile"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected cleanups in order %v, got %v", expected, order)
+	}
+}
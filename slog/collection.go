@@ -0,0 +1,89 @@
+package slog
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+)
+
+// replaceMaxCollectionLen returns a ReplaceAttr function that truncates
+// collection values longer than the config's MaxCollectionLen, or nil if no
+// limit is configured.
+//
+// Values of kind slog.KindAny holding a slice, array or map are handled.
+// A truncated slice or array becomes a []any of its first elements followed by
+// a "…(+K more)" marker element. A truncated map becomes a map[string]any of
+// the entries with the smallest keys, formatted with fmt, plus a "…(+K more)"
+// key with a nil value. Byte slices and collections that format themselves
+// (fmt.Stringer, error, json.Marshaler, encoding.TextMarshaler) are left as
+// they are, as are collections nested inside the kept elements.
+func replaceMaxCollectionLen(config *Config) func([]string, slog.Attr) slog.Attr {
+	if config.MaxCollectionLen <= 0 {
+		return nil
+	}
+
+	limit := config.MaxCollectionLen
+	return func(_ []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() != slog.KindAny {
+			return a
+		}
+		if v, ok := truncateCollection(a.Value.Any(), limit); ok {
+			a.Value = slog.AnyValue(v)
+		}
+		return a
+	}
+}
+
+// truncateCollection returns v truncated to limit elements, and whether v is
+// a collection that was truncated.
+func truncateCollection(v any, limit int) (any, bool) {
+	switch v.(type) {
+	case nil, []byte, fmt.Stringer, error, json.Marshaler, encoding.TextMarshaler:
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		n := rv.Len()
+		if n <= limit {
+			return nil, false
+		}
+		kept := make([]any, 0, limit+1)
+		for i := 0; i < limit; i++ {
+			kept = append(kept, rv.Index(i).Interface())
+		}
+		return append(kept, moreMarker(n-limit)), true
+	case reflect.Map:
+		n := rv.Len()
+		if n <= limit {
+			return nil, false
+		}
+		type entry struct {
+			key   string
+			value any
+		}
+		entries := make([]entry, 0, n)
+		for iter := rv.MapRange(); iter.Next(); {
+			entries = append(entries, entry{fmt.Sprint(iter.Key().Interface()), iter.Value().Interface()})
+		}
+		// Sort so that the kept entries do not depend on the map iteration order.
+		slices.SortFunc(entries, func(a, b entry) int { return cmp.Compare(a.key, b.key) })
+		kept := make(map[string]any, limit+1)
+		for _, e := range entries[:limit] {
+			kept[e.key] = e.value
+		}
+		kept[moreMarker(n-limit)] = nil
+		return kept, true
+	}
+	return nil, false
+}
+
+// moreMarker returns the marker of n truncated elements.
+func moreMarker(n int) string {
+	return fmt.Sprintf("…(+%d more)", n)
+}
@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"testing"
+)
+
+func TestReplaceMaxCollectionLen(t *testing.T) {
+	config := &Config{HandlerType: "json", MaxCollectionLen: 3}
+
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"Slice", []int{1, 2, 3, 4, 5}, `[1,2,3,"…(+2 more)"]`},
+		{"Array", [4]string{"a", "b", "c", "d"}, `["a","b","c","…(+1 more)"]`},
+		{"Map", map[string]int{"e": 5, "a": 1, "d": 4, "b": 2, "c": 3}, `{"a":1,"b":2,"c":3,"…(+2 more)":null}`},
+		{"Map With Int Keys", map[int]bool{3: true, 1: true, 2: false, 4: true}, `{"1":true,"2":false,"3":true,"…(+1 more)":null}`},
+		{"Short Slice", []int{1, 2, 3}, `[1,2,3]`},
+		{"Short Map", map[string]int{"a": 1}, `{"a":1}`},
+		{"Bytes", []byte("abcdef"), `"YWJjZGVm"`},
+		{"Stringer", net.IP{192, 0, 2, 1}, `"192.0.2.1"`},
+		{"Nested", [][]int{{1, 2, 3, 4}, {5}, {6}, {7}}, `[[1,2,3,4],[5],[6],"…(+1 more)"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(createHandler(config, &buf))
+			logger.Info("test", slog.Group("g", "v", tt.value))
+
+			var record struct {
+				G struct {
+					V json.RawMessage `json:"v"`
+				} `json:"g"`
+			}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+			}
+			if got := string(record.G.V); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReadConfigMaxCollectionLen(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerMaxCollectionLen: "10"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.MaxCollectionLen != 10 {
+		t.Errorf("expected a limit of 10, got %d", config.MaxCollectionLen)
+	}
+
+	for _, value := range []string{"0", "-1", "many"} {
+		if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerMaxCollectionLen: value}); !errors.Is(err, ErrInvalidMaxCollectionLen) {
+			t.Errorf("%q: expected ErrInvalidMaxCollectionLen, got %v", value, err)
+		}
+	}
+}
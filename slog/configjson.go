@@ -0,0 +1,205 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// currentConfig is the configuration most recently applied by Init.
+var currentConfig atomic.Pointer[Config]
+
+// CurrentConfig returns a copy of the configuration that Init most recently
+// applied to the default logger, or nil if Init has not configured it.
+func CurrentConfig() *Config {
+	config := currentConfig.Load()
+	if config == nil {
+		return nil
+	}
+	c := *config
+	c.Outputs = slices.Clone(config.Outputs)
+	c.AttrAllowlist = slices.Clone(config.AttrAllowlist)
+	c.HashKeys = slices.Clone(config.HashKeys)
+	c.MaskPatterns = slices.Clone(config.MaskPatterns)
+	c.MsgLevelOverrides = slices.Clone(config.MsgLevelOverrides)
+	c.EnvAttrs = slices.Clone(config.EnvAttrs)
+	c.RenameKeys = maps.Clone(config.RenameKeys)
+	c.audit = nil
+	c.warnings = nil
+	return &c
+}
+
+// configJSON is the JSON representation of a Config.
+type configJSON struct {
+	Level                  string            `json:"level"`
+	NoteSuppressed         int               `json:"note_suppressed,omitempty"`
+	MsgLevelOverrides      []string          `json:"msg_level_overrides,omitempty"`
+	AddSource              bool              `json:"add_source"`
+	SourceLevel            string            `json:"source_level,omitempty"`
+	SourceKey              string            `json:"source_key,omitempty"`
+	SourceFlatten          bool              `json:"source_flatten,omitempty"`
+	Handler                string            `json:"handler"`
+	JSONHTMLEscape         bool              `json:"json_html_escape,omitempty"`
+	JSONFast               bool              `json:"json_fast,omitempty"`
+	JSONArray              bool              `json:"json_array,omitempty"`
+	GELFHost               string            `json:"gelf_host,omitempty"`
+	Writer                 string            `json:"writer"`
+	Outputs                []outputJSON      `json:"outputs,omitempty"`
+	AuditMarkerKey         string            `json:"audit_marker_key,omitempty"`
+	AuditOutput            *outputJSON       `json:"audit_output,omitempty"`
+	AuditStripMarker       bool              `json:"audit_strip_marker,omitempty"`
+	WriterFilePath         string            `json:"writer_file_path,omitempty"`
+	WriterFileNoAppend     bool              `json:"writer_file_no_append"`
+	WriterFilePerm         string            `json:"writer_file_perm"`
+	WriterProbe            bool              `json:"writer_probe,omitempty"`
+	WriterFileIndex        bool              `json:"writer_file_index,omitempty"`
+	UnixSocketPath         string            `json:"unix_socket_path,omitempty"`
+	UnixSocketType         string            `json:"unix_socket_type,omitempty"`
+	BrokenPipeAction       string            `json:"broken_pipe_action,omitempty"`
+	NilAttrAction          string            `json:"nil_attr_action,omitempty"`
+	NilAttrPlaceholder     string            `json:"nil_attr_placeholder,omitempty"`
+	WriterBreakerThreshold int               `json:"writer_breaker_threshold,omitempty"`
+	WriterBreakerCooldown  string            `json:"writer_breaker_cooldown,omitempty"`
+	WriteTimeout           string            `json:"write_timeout,omitempty"`
+	Framing                string            `json:"framing,omitempty"`
+	MaxLineBytes           int               `json:"max_line_bytes,omitempty"`
+	MaxLineAction          string            `json:"max_line_action,omitempty"`
+	AttrAllowlist          []string          `json:"attr_allowlist,omitempty"`
+	HashKeys               []string          `json:"hash_keys,omitempty"`
+	HashLength             int               `json:"hash_length,omitempty"`
+	MaskPatterns           []string          `json:"mask_patterns,omitempty"`
+	RedactLevel            string            `json:"redact_level,omitempty"`
+	EnvAttrKeys            []string          `json:"env_attr_keys,omitempty"`
+	DedupKeys              string            `json:"dedup_keys,omitempty"`
+	ProtectBuiltins        bool              `json:"protect_builtins,omitempty"`
+	SortAttrs              bool              `json:"sort_attrs,omitempty"`
+	MaxAttrs               int               `json:"max_attrs,omitempty"`
+	MaxCollectionLen       int               `json:"max_collection_len,omitempty"`
+	RenameKeys             map[string]string `json:"rename_keys,omitempty"`
+	Timezone               string            `json:"timezone,omitempty"`
+	AddGoID                bool              `json:"add_goid,omitempty"`
+	AddUptime              bool              `json:"add_uptime,omitempty"`
+	AddModule              bool              `json:"add_module,omitempty"`
+	StacktraceOnce         bool              `json:"stacktrace_once,omitempty"`
+	AddCtxCause            bool              `json:"ctx_cause,omitempty"`
+	ContextStrict          bool              `json:"context_strict,omitempty"`
+	SampleTargetRPS        float64           `json:"sample_target_rps,omitempty"`
+	HeartbeatInterval      string            `json:"heartbeat_interval,omitempty"`
+	PIDFile                string            `json:"pidfile,omitempty"`
+	NoPanicOnError         bool              `json:"no_panic_on_error"`
+	Strict                 bool              `json:"strict"`
+}
+
+// outputJSON is the JSON representation of an Output.
+type outputJSON struct {
+	Handler        string `json:"handler"`
+	Writer         string `json:"writer"`
+	WriterFilePath string `json:"writer_file_path,omitempty"`
+}
+
+// ConfigJSON returns the configuration reported by CurrentConfig as JSON, for
+// example to expose it on an admin endpoint when debugging a misconfiguration.
+// Levels and durations are rendered as strings and the file permission as an
+// octal string ("0644"). The hash salt and the values of LOGGER_ATTRS_FROM_ENV
+// are omitted since they may hold sensitive data. It returns
+// "null" if Init has not configured the default logger.
+func ConfigJSON() ([]byte, error) {
+	config := CurrentConfig()
+	if config == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(newConfigJSON(config))
+}
+
+// newConfigJSON converts config to its JSON representation.
+func newConfigJSON(config *Config) configJSON {
+	c := configJSON{
+		Level:                  config.Level.String(),
+		NoteSuppressed:         config.NoteSuppressed,
+		AddSource:              config.AddSource,
+		SourceKey:              config.SourceKey,
+		SourceFlatten:          config.SourceFlatten,
+		Handler:                config.HandlerType,
+		JSONHTMLEscape:         config.JSONHTMLEscape,
+		JSONFast:               config.JSONFast,
+		JSONArray:              config.JSONArray,
+		GELFHost:               config.GELFHost,
+		Writer:                 config.WriterType,
+		WriterFilePath:         config.WriterFilePath,
+		WriterFileNoAppend:     config.WriterFileNoAppend,
+		WriterFilePerm:         fmt.Sprintf("%#04o", config.WriterFilePerm.Perm()),
+		WriterProbe:            config.WriterProbe,
+		WriterFileIndex:        config.WriterFileIndex,
+		UnixSocketPath:         config.UnixSocketPath,
+		UnixSocketType:         config.UnixSocketType,
+		BrokenPipeAction:       config.BrokenPipeAction,
+		NilAttrAction:          config.NilAttrAction,
+		NilAttrPlaceholder:     config.NilAttrPlaceholder,
+		WriterBreakerThreshold: config.WriterBreakerThreshold,
+		Framing:                config.Framing,
+		MaxLineBytes:           config.MaxLineBytes,
+		MaxLineAction:          config.MaxLineAction,
+		AttrAllowlist:          config.AttrAllowlist,
+		HashKeys:               config.HashKeys,
+		HashLength:             config.HashLength,
+		DedupKeys:              config.DedupKeys,
+		ProtectBuiltins:        config.ProtectBuiltins,
+		SortAttrs:              config.SortAttrs,
+		MaxAttrs:               config.MaxAttrs,
+		MaxCollectionLen:       config.MaxCollectionLen,
+		RenameKeys:             config.RenameKeys,
+		AddGoID:                config.AddGoID,
+		AddUptime:              config.AddUptime,
+		AddModule:              config.AddModule,
+		StacktraceOnce:         config.StacktraceOnce,
+		AddCtxCause:            config.AddCtxCause,
+		ContextStrict:          config.ContextStrict,
+		PIDFile:                config.PIDFile,
+		NoPanicOnError:         config.NoPanicOnError,
+		Strict:                 config.Strict,
+	}
+	if config.leveler != nil {
+		c.Level = config.leveler.Level().String()
+	}
+	if config.SourceLevel != nil {
+		c.SourceLevel = config.SourceLevel.Level().String()
+	}
+	if config.RedactLevel != nil {
+		c.RedactLevel = config.RedactLevel.Level().String()
+	}
+	for _, o := range config.Outputs {
+		c.Outputs = append(c.Outputs, outputJSON{Handler: o.HandlerType, Writer: o.WriterType, WriterFilePath: o.WriterFilePath})
+	}
+	if config.AuditMarkerKey != "" {
+		c.AuditMarkerKey = config.AuditMarkerKey
+		c.AuditOutput = &outputJSON{Handler: config.AuditOutput.HandlerType, Writer: config.AuditOutput.WriterType, WriterFilePath: config.AuditOutput.WriterFilePath}
+		c.AuditStripMarker = config.AuditStripMarker
+	}
+	if config.WriterBreakerThreshold > 0 {
+		c.WriterBreakerCooldown = config.WriterBreakerCooldown.String()
+	}
+	if config.WriteTimeout > 0 {
+		c.WriteTimeout = config.WriteTimeout.String()
+	}
+	for _, o := range config.MsgLevelOverrides {
+		c.MsgLevelOverrides = append(c.MsgLevelOverrides, o.String())
+	}
+	for _, re := range config.MaskPatterns {
+		c.MaskPatterns = append(c.MaskPatterns, re.String())
+	}
+	for _, a := range config.EnvAttrs {
+		c.EnvAttrKeys = append(c.EnvAttrKeys, a.Key)
+	}
+	if config.Timezone != nil {
+		c.Timezone = config.Timezone.String()
+	}
+	if config.SampleAdaptive {
+		c.SampleTargetRPS = config.SampleTargetRPS
+	}
+	if config.HeartbeatInterval > 0 {
+		c.HeartbeatInterval = config.HeartbeatInterval.String()
+	}
+	return c
+}
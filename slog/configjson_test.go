@@ -0,0 +1,91 @@
+package slog
+
+import (
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigJSON(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	defer Close()
+
+	path := filepath.Join(t.TempDir(), "test.log")
+	clearEnvVars()
+	t.Setenv(EnvLoggerLevel, "warn")
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, path)
+	t.Setenv(EnvLoggerWriterFilePerm, "0600")
+	t.Setenv(EnvLoggerTimezone, "UTC")
+	t.Setenv(EnvLoggerAttrsFromEnv, "token=TEST_SECRET_TOKEN")
+	t.Setenv("TEST_SECRET_TOKEN", "s3cr3t")
+	Init()
+
+	data, err := ConfigJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "s3cr3t") {
+		t.Errorf("expected the attribute value to be omitted, got %s", data)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	expected := map[string]any{
+		"level":                 "WARN",
+		"add_source":            false,
+		"handler":               "text",
+		"writer":                "file",
+		"writer_file_path":      path,
+		"writer_file_no_append": false,
+		"writer_file_perm":      "0600",
+		"env_attr_keys":         []any{"token"},
+		"timezone":              "UTC",
+		"no_panic_on_error":     false,
+		"strict":                false,
+	}
+	if len(got) != len(expected) {
+		t.Errorf("expected keys %v, got %s", expected, data)
+	}
+	for k, v := range expected {
+		if gotJSON, _ := json.Marshal(got[k]); string(gotJSON) != mustMarshal(t, v) {
+			t.Errorf("%s: expected %v, got %s", k, v, gotJSON)
+		}
+	}
+}
+
+func TestCurrentConfigCopy(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	clearEnvVars()
+	t.Setenv(EnvLoggerAttrAllowlist, "a,b")
+	Init()
+
+	config := CurrentConfig()
+	if config == nil {
+		t.Fatal("expected the current config")
+	}
+	config.AttrAllowlist[0] = "changed"
+	if CurrentConfig().AttrAllowlist[0] != "a" {
+		t.Errorf("expected CurrentConfig to return a copy")
+	}
+}
+
+func mustMarshal(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
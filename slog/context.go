@@ -0,0 +1,184 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithContext returns a copy of ctx that carries the given logger, so that
+// context-aware logs made with the returned context use it.
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ContextLoggerKey{}, logger)
+}
+
+// Scope returns a copy of ctx whose context logger opens a group with the
+// given name, so that attributes logged within the scope are nested under it.
+// The scope is based on the logger already stored in ctx, or slog.Default().
+// It ends naturally when the returned context goes out of use.
+func Scope(ctx context.Context, name string) context.Context {
+	return WithContext(ctx, FromContext(ctx).WithGroup(name))
+}
+
+// WithError returns a copy of ctx whose context logger adds the given error as
+// an "error" attribute to every subsequent log. Calling WithError again
+// replaces the error rather than adding a second attribute, and keeps the
+// attributes and groups added to the context logger since the first call, for
+// example by Scope.
+func WithError(ctx context.Context, err error) context.Context {
+	base, ops := FromContext(ctx), []handlerOp(nil)
+	if h, ok := contextLoggerBase(ctx).(*errorHandler); ok {
+		base, ops = h.base, h.ops
+	}
+	return WithContext(ctx, newContextLogger(newErrorHandler(base, err, ops)))
+}
+
+// ClearError returns a copy of ctx whose context logger no longer adds the
+// error attached by WithError. The attributes and groups added to the context
+// logger since WithError, for example by Scope, are kept. If no error is
+// attached, ctx is returned unchanged.
+func ClearError(ctx context.Context) context.Context {
+	h, ok := contextLoggerBase(ctx).(*errorHandler)
+	if !ok {
+		return ctx
+	}
+	if len(h.ops) == 0 {
+		return WithContext(ctx, h.base)
+	}
+	return WithContext(ctx, slog.New(applyHandlerOps(h.base.Handler(), h.ops)))
+}
+
+// contextLoggerHandler is the outermost handler of the context loggers created
+// by WithError, Tags, WithLevel and WithMiddleware. Records logged through
+// such a logger directly, or through a logger derived from it, have their
+// context marked with the logger, so that the context-aware handler they
+// reach does not pass them to the context logger a second time. The handlers
+// derived from one contextLoggerHandler share its id.
+type contextLoggerHandler struct {
+	next slog.Handler
+	id   *contextLoggerID
+}
+
+// contextLoggerID identifies a context logger created by this package and the
+// loggers derived from it. It is not empty, so that distinct ids never compare
+// equal.
+type contextLoggerID struct {
+	_ byte
+}
+
+// newContextLogger returns a context logger that logs to handler.
+func newContextLogger(handler slog.Handler) *slog.Logger {
+	return slog.New(&contextLoggerHandler{next: handler, id: new(contextLoggerID)})
+}
+
+// contextLoggerBase returns the handler of the context logger of ctx, without
+// the contextLoggerHandler of a context logger created by this package.
+func contextLoggerBase(ctx context.Context) slog.Handler {
+	handler := FromContext(ctx).Handler()
+	if h, ok := handler.(*contextLoggerHandler); ok {
+		return h.next
+	}
+	return handler
+}
+
+// contextLoggerKey returns the key that identifies logger in the context
+// loggers a record entered.
+func contextLoggerKey(logger *slog.Logger) any {
+	if h, ok := logger.Handler().(*contextLoggerHandler); ok {
+		return h.id
+	}
+	return logger
+}
+
+// enter marks ctx as having entered h's context logger, unless the record
+// already entered a context logger.
+func (h *contextLoggerHandler) enter(ctx context.Context) context.Context {
+	if ctx == nil || ctx.Value(contextDelegationKey{}) != nil {
+		return ctx
+	}
+	return enterContextLogger(ctx, h.id, false)
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *contextLoggerHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(h.enter(ctx), level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *contextLoggerHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(h.enter(ctx), r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *contextLoggerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextLoggerHandler{next: h.next.WithAttrs(attrs), id: h.id}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *contextLoggerHandler) WithGroup(name string) slog.Handler {
+	return &contextLoggerHandler{next: h.next.WithGroup(name), id: h.id}
+}
+
+// describe implements describer.
+func (h *contextLoggerHandler) describe() string {
+	return "context_logger"
+}
+
+// unwrap implements describer.
+func (h *contextLoggerHandler) unwrap() slog.Handler {
+	return h.next
+}
+
+// errorHandler is the handler of the context logger returned by WithError. It
+// remembers the logger the error was attached to and the operations applied
+// since, so that the error can be replaced or removed without losing them.
+type errorHandler struct {
+	base *slog.Logger
+	err  error
+	ops  []handlerOp
+	next slog.Handler
+}
+
+// newErrorHandler creates a handler that adds err to the records of base and
+// then applies ops.
+func newErrorHandler(base *slog.Logger, err error, ops []handlerOp) *errorHandler {
+	next := base.Handler().WithAttrs([]slog.Attr{slog.Any("error", err)})
+	return &errorHandler{base: base, err: err, ops: ops, next: applyHandlerOps(next, ops)}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *errorHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *errorHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *errorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(handlerOp{attrs: attrs}, h.next.WithAttrs(attrs))
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *errorHandler) WithGroup(name string) slog.Handler {
+	return h.derive(handlerOp{group: name}, h.next.WithGroup(name))
+}
+
+// derive returns a handler with op appended to h's operations and next as the
+// handler it forwards to.
+func (h *errorHandler) derive(op handlerOp, next slog.Handler) *errorHandler {
+	ops := make([]handlerOp, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &errorHandler{base: h.base, err: h.err, ops: append(ops, op), next: next}
+}
+
+// describe implements describer.
+func (h *errorHandler) describe() string {
+	return "error"
+}
+
+// unwrap implements describer.
+func (h *errorHandler) unwrap() slog.Handler {
+	return h.next
+}
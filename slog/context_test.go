@@ -0,0 +1,104 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithContext(t *testing.T) {
+	logger := slog.New(newTestBufferHandler())
+	ctx := WithContext(context.Background(), logger)
+
+	if got := FromContext(ctx); got != logger {
+		t.Errorf("expected FromContext to return the stored logger")
+	}
+}
+
+func TestScope(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil)).With("RequestID", "req-1")
+	ctx := WithContext(context.Background(), logger)
+
+	scoped := Scope(ctx, "db")
+	FromContext(scoped).InfoContext(scoped, "query", "rows", 3)
+
+	output := buf.String()
+	if !strings.Contains(output, "RequestID=req-1") || !strings.Contains(output, "db.rows=3") {
+		t.Errorf("expected request attribute and scoped attribute, got %q", output)
+	}
+
+	// The original context is unaffected by the scope
+	buf.Reset()
+	FromContext(ctx).InfoContext(ctx, "outside", "rows", 3)
+	if strings.Contains(buf.String(), "db.rows") {
+		t.Errorf("expected no scope outside of it, got %q", buf.String())
+	}
+}
+
+func TestWithError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil)).With("RequestID", "req-1")
+	ctx := WithContext(context.Background(), logger)
+
+	ctx = WithError(ctx, errors.New("first"))
+	ctx = WithError(ctx, errors.New("second"))
+	FromContext(ctx).InfoContext(ctx, "failed")
+
+	output := buf.String()
+	if !strings.Contains(output, "RequestID=req-1") || !strings.Contains(output, "error=second") {
+		t.Errorf("expected request attribute and error, got %q", output)
+	}
+	if strings.Contains(output, "error=first") {
+		t.Errorf("expected the error to be replaced, got %q", output)
+	}
+
+	buf.Reset()
+	ctx = ClearError(ctx)
+	FromContext(ctx).InfoContext(ctx, "recovered")
+
+	output = buf.String()
+	if !strings.Contains(output, "RequestID=req-1") || strings.Contains(output, "error=") {
+		t.Errorf("expected request attribute without error, got %q", output)
+	}
+	if got := FromContext(ctx); got != logger {
+		t.Errorf("expected ClearError to restore the original logger")
+	}
+}
+
+func TestScopeThenWithError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	ctx := WithContext(context.Background(), logger)
+
+	ctx = WithError(ctx, errors.New("first"))
+	ctx = Scope(ctx, "db")
+	ctx = WithError(ctx, errors.New("second"))
+	FromContext(ctx).InfoContext(ctx, "failed", "rows", 3)
+
+	// The scope opened between the calls is kept, and the error stays at the
+	// top level where it was attached
+	output := buf.String()
+	if !strings.Contains(output, " error=second db.rows=3") || strings.Contains(output, "error=first") {
+		t.Errorf("expected the replaced error and the scope, got %q", output)
+	}
+
+	buf.Reset()
+	ctx = ClearError(ctx)
+	FromContext(ctx).InfoContext(ctx, "recovered", "rows", 3)
+
+	output = buf.String()
+	if !strings.Contains(output, "db.rows=3") || strings.Contains(output, "error=") {
+		t.Errorf("expected the scope without error, got %q", output)
+	}
+}
+
+func TestClearErrorWithoutError(t *testing.T) {
+	ctx := context.Background()
+	if got := ClearError(ctx); got != ctx {
+		t.Errorf("expected ClearError to return the context unchanged")
+	}
+}
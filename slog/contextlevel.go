@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLevel returns a copy of ctx whose context logger logs at the given
+// level, regardless of the level of the logger it is based on, for example to
+// log a single request at debug level. The context logger is based on the
+// logger already stored in ctx, or slog.Default().
+//
+// The level replaces the logger's own level check; wrappers that filter by
+// level while handling records, such as the one of LOGGER_NOTE_SUPPRESSED,
+// still apply theirs.
+func WithLevel(ctx context.Context, level slog.Leveler) context.Context {
+	return WithContext(ctx, newContextLogger(newLevelOverrideHandler(contextLoggerBase(ctx), level)))
+}
+
+// levelOverrideHandler is a wrapper handler that replaces the level check of
+// the handler it wraps with its own level.
+type levelOverrideHandler struct {
+	next  slog.Handler
+	level slog.Leveler
+}
+
+// newLevelOverrideHandler creates a new handler that logs the records of at
+// least level to next. A level override of next is replaced.
+func newLevelOverrideHandler(next slog.Handler, level slog.Leveler) slog.Handler {
+	if h, ok := next.(*levelOverrideHandler); ok {
+		next = h.next
+	}
+	return &levelOverrideHandler{next: next, level: level}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *levelOverrideHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *levelOverrideHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *levelOverrideHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelOverrideHandler{next: h.next.WithAttrs(attrs), level: h.level}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *levelOverrideHandler) WithGroup(name string) slog.Handler {
+	return &levelOverrideHandler{next: h.next.WithGroup(name), level: h.level}
+}
+
+// describe implements describer.
+func (h *levelOverrideHandler) describe() string {
+	return "level_override"
+}
+
+// unwrap implements describer.
+func (h *levelOverrideHandler) unwrap() slog.Handler {
+	return h.next
+}
@@ -0,0 +1,34 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare", Level: slog.LevelInfo}, &buf))
+	ctx := WithContext(context.Background(), logger.With("RequestID", "req-1"))
+
+	debugCtx := WithLevel(ctx, slog.LevelDebug)
+	FromContext(debugCtx).DebugContext(debugCtx, "verbose")
+	// The default-style context-aware logger defers to the context logger
+	logger.DebugContext(debugCtx, "through the base logger")
+	logger.DebugContext(ctx, "hidden")
+
+	// A nested override replaces the previous one
+	errorCtx := WithLevel(debugCtx, slog.LevelError)
+	FromContext(errorCtx).WarnContext(errorCtx, "hidden")
+	FromContext(errorCtx).ErrorContext(errorCtx, "shown")
+
+	expected := "msg=verbose RequestID=req-1\nmsg=\"through the base logger\" RequestID=req-1\nmsg=shown RequestID=req-1\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+	if names := describeHandler(FromContext(errorCtx).Handler()); len(names) < 2 || names[1] != "level_override" || strings.Count(strings.Join(names, ","), "level_override") != 1 {
+		t.Errorf("expected a single level override, got %v", names)
+	}
+}
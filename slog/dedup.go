@@ -0,0 +1,101 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// dedupHandler is a wrapper handler that removes attributes with duplicate
+// keys from each record, following policy ("first" keeps the first value of
+// a key, "last" the last one). Kept attributes stay at the position of the
+// first occurrence of their key.
+//
+// Attributes added with WithAttrs are held by the handler rather than passed
+// on, so that they can be merged with the record's attributes. They are passed
+// on when a group is opened; keys are only compared within the same group.
+type dedupHandler struct {
+	next   slog.Handler
+	policy string
+	attrs  []slog.Attr
+}
+
+// newDedupHandler creates a new handler that removes duplicate keys with the given policy.
+func newDedupHandler(next slog.Handler, policy string) slog.Handler {
+	return &dedupHandler{next: next, policy: policy}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *dedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *dedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(h.attrs)+r.NumAttrs())
+	attrs = append(attrs, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	merged := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	merged.AddAttrs(dedupAttrs(attrs, h.policy)...)
+	return h.next.Handle(ctx, merged)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *dedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	held := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	held = append(held, h.attrs...)
+	return &dedupHandler{next: h.next, policy: h.policy, attrs: append(held, attrs...)}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *dedupHandler) WithGroup(name string) slog.Handler {
+	next := h.next
+	if len(h.attrs) > 0 {
+		next = next.WithAttrs(dedupAttrs(append([]slog.Attr(nil), h.attrs...), h.policy))
+	}
+	return &dedupHandler{next: next.WithGroup(name), policy: h.policy}
+}
+
+// describe implements describer.
+func (h *dedupHandler) describe() string {
+	return "dedup(" + h.policy + ")"
+}
+
+// unwrap implements describer.
+func (h *dedupHandler) unwrap() slog.Handler {
+	return h.next
+}
+
+// dedupAttrs removes the attributes with duplicate keys from attrs in place
+// following policy and returns the shortened slice.
+func dedupAttrs(attrs []slog.Attr, policy string) []slog.Attr {
+	index := make(map[string]int, len(attrs))
+	deduped := attrs[:0]
+	for _, a := range attrs {
+		i, seen := index[a.Key]
+		if !seen {
+			index[a.Key] = len(deduped)
+			deduped = append(deduped, a)
+		} else if policy == "last" {
+			deduped[i] = a
+		}
+	}
+	return deduped
+}
+
+// dedupRecord returns a copy of r without the attributes with duplicate keys,
+// following policy.
+func dedupRecord(r slog.Record, policy string) slog.Record {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	deduped := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	deduped.AddAttrs(dedupAttrs(attrs, policy)...)
+	return deduped
+}
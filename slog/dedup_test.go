@@ -0,0 +1,100 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestDedupHandler(t *testing.T) {
+	tests := []struct {
+		policy   string
+		expected string
+		grouped  string
+	}{
+		{
+			policy:   "first",
+			expected: "msg=test request_id=ctx-1 user=u-1 g.k=1",
+			grouped:  "msg=test request_id=ctx-1 user=u-1 g.k=1 g.v=2",
+		},
+		{
+			policy:   "last",
+			expected: "msg=test request_id=rec-1 user=u-2 g.k=1",
+			grouped:  "msg=test request_id=ctx-1 user=u-2 g.k=3 g.v=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			var buf bytes.Buffer
+			base := slog.New(createHandler(&Config{HandlerType: "bare", DedupKeys: tt.policy}, &buf))
+			contextLogger := base.With("request_id", "ctx-1", "user", "u-1").With("user", "u-2")
+			ctx := WithContext(context.Background(), contextLogger)
+
+			// Record attributes collide with the context logger's attributes
+			base.InfoContext(ctx, "test", "request_id", "rec-1", slog.Group("g", "k", 1))
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+
+			// Keys are compared within each group
+			buf.Reset()
+			contextLogger.WithGroup("g").With("k", 1).Info("test", "v", 2, "k", 3)
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.grouped {
+				t.Errorf("expected %q, got %q", tt.grouped, got)
+			}
+		})
+	}
+}
+
+func TestReadConfigDedupKeys(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerDedupKeys: "Last"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.DedupKeys != "last" {
+		t.Errorf("expected policy 'last', got %q", config.DedupKeys)
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerDedupKeys: "random"}); !errors.Is(err, ErrInvalidDedupPolicy) {
+		t.Errorf("expected ErrInvalidDedupPolicy, got %v", err)
+	}
+}
+
+func TestDedupContextLogger(t *testing.T) {
+	var contextBuf, defaultBuf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare", DedupKeys: "first"}, &defaultBuf))
+
+	// The context logger does not remove duplicate keys itself
+	contextLogger := slog.New(slog.NewTextHandler(&contextBuf, &slog.HandlerOptions{ReplaceAttr: removeTime}))
+	ctx := WithContext(context.Background(), contextLogger)
+	ctx = WithLazyAttrs(ctx, func(ctx context.Context) []slog.Attr {
+		return []slog.Attr{slog.String("request_id", "ctx-1")}
+	})
+
+	logger.InfoContext(ctx, "lazy", "request_id", "rec-1")
+	logger.InfoContext(ctx, "record", "user", "u-1", "user", "u-2")
+
+	// Context loggers created by this package keep the policy
+	ctx = WithError(WithContext(context.Background(), logger.With("id", 1)), errors.New("boom"))
+	logger.InfoContext(ctx, "error", "error", "other", "id", 2)
+	logger.InfoContext(Tags(ctx, "db"), "tags", "tags", "other")
+
+	expected := "level=INFO msg=lazy request_id=rec-1\n" +
+		"level=INFO msg=record user=u-1 request_id=ctx-1\n"
+	if contextBuf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, contextBuf.String())
+	}
+	expected = "msg=error id=1 error=boom\n" +
+		"msg=tags id=1 error=boom tags=other\n"
+	if defaultBuf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, defaultBuf.String())
+	}
+}
@@ -0,0 +1,89 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+var (
+	derivedAttrsMu sync.RWMutex
+	derivedAttrs   []func(r slog.Record) (slog.Attr, bool)
+)
+
+// RegisterDerivedAttr registers a function that computes an attribute from
+// each record, for example a severity bucket from the level or a category
+// from a message prefix. If fn reports true, its attribute is added to the
+// record before it is logged. Functions run in order of registration on the
+// logging goroutine, so they must be cheap and safe for concurrent use. The
+// record passed to fn holds the attributes of the logging call, but not those
+// added with With, nor those derived by other functions.
+//
+// Derived attributes are added by the loggers built after the functions are
+// registered, so register them before calling Init or Build. They are added
+// before the attribute settings such as LOGGER_DEDUP_KEYS and
+// LOGGER_MAX_ATTRS apply.
+func RegisterDerivedAttr(fn func(r slog.Record) (slog.Attr, bool)) {
+	derivedAttrsMu.Lock()
+	defer derivedAttrsMu.Unlock()
+	derivedAttrs = append(derivedAttrs, fn)
+}
+
+// registeredDerivedAttrs returns a copy of the registered derived attribute functions.
+func registeredDerivedAttrs() []func(r slog.Record) (slog.Attr, bool) {
+	derivedAttrsMu.RLock()
+	defer derivedAttrsMu.RUnlock()
+	return append([]func(r slog.Record) (slog.Attr, bool)(nil), derivedAttrs...)
+}
+
+// derivedHandler is a wrapper handler that adds the attributes computed by
+// the derived attribute functions to each record.
+type derivedHandler struct {
+	next slog.Handler
+	fns  []func(r slog.Record) (slog.Attr, bool)
+}
+
+// newDerivedHandler creates a new handler that adds the attributes derived by fns.
+func newDerivedHandler(next slog.Handler, fns []func(r slog.Record) (slog.Attr, bool)) slog.Handler {
+	return &derivedHandler{next: next, fns: fns}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *derivedHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *derivedHandler) Handle(ctx context.Context, r slog.Record) error {
+	var derived []slog.Attr
+	for _, fn := range h.fns {
+		if a, ok := fn(r); ok {
+			derived = append(derived, a)
+		}
+	}
+	if len(derived) > 0 {
+		r = r.Clone()
+		r.AddAttrs(derived...)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *derivedHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &derivedHandler{next: h.next.WithAttrs(attrs), fns: h.fns}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *derivedHandler) WithGroup(name string) slog.Handler {
+	return &derivedHandler{next: h.next.WithGroup(name), fns: h.fns}
+}
+
+// describe implements describer.
+func (h *derivedHandler) describe() string {
+	return "derived"
+}
+
+// unwrap implements describer.
+func (h *derivedHandler) unwrap() slog.Handler {
+	return h.next
+}
@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func resetDerivedAttrs() {
+	derivedAttrsMu.Lock()
+	defer derivedAttrsMu.Unlock()
+	derivedAttrs = nil
+}
+
+func TestRegisterDerivedAttr(t *testing.T) {
+	defer resetDerivedAttrs()
+
+	RegisterDerivedAttr(func(r slog.Record) (slog.Attr, bool) {
+		if r.Level >= slog.LevelWarn {
+			return slog.String("bucket", "high"), true
+		}
+		return slog.String("bucket", "low"), true
+	})
+	RegisterDerivedAttr(func(r slog.Record) (slog.Attr, bool) {
+		category, _, ok := strings.Cut(r.Message, ":")
+		return slog.String("category", category), ok
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare"}, &buf))
+	logger.Info("cache: miss", "key", "k1")
+	logger.With("service", "api").Error("request failed")
+
+	expected := "msg=\"cache: miss\" key=k1 bucket=low category=cache\nmsg=\"request failed\" service=api bucket=high\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+	if names := describeHandler(logger.Handler()); !strings.Contains(strings.Join(names, ","), "derived") {
+		t.Errorf("expected a derived handler, got %v", names)
+	}
+}
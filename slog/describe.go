@@ -0,0 +1,60 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// describer is implemented by the handler wrappers of this package so that
+// the handler chain can be walked for introspection.
+type describer interface {
+	// describe returns the name of the wrapper.
+	describe() string
+	// unwrap returns the handler the wrapper passes records to, or nil if it
+	// has no single next handler.
+	unwrap() slog.Handler
+}
+
+// DescribeHandler returns the names of the handlers making up the default
+// logger's handler, from the outermost wrapper to the base handler, for
+// example ["context", "swappable", "sampler", "goid", "output", "json"]. It is meant as
+// a debugging aid; the names are not stable API.
+func DescribeHandler() []string {
+	return describeHandler(slog.Default().Handler())
+}
+
+// describeHandler returns the names of the handlers in the chain starting at handler.
+func describeHandler(handler slog.Handler) []string {
+	var names []string
+	for handler != nil {
+		d, ok := handler.(describer)
+		if !ok {
+			names = append(names, baseHandlerName(handler))
+			break
+		}
+		names = append(names, d.describe())
+		handler = d.unwrap()
+	}
+	return names
+}
+
+// baseHandlerName returns the name of a handler that does not wrap another one.
+func baseHandlerName(handler slog.Handler) string {
+	switch h := handler.(type) {
+	case *slog.JSONHandler:
+		return "json"
+	case *slog.TextHandler:
+		return "text"
+	case *fastJSONHandler:
+		return "json(fast)"
+	case *encoderHandler:
+		if _, ok := h.enc.(*gelfEncoder); ok {
+			return "gelf"
+		}
+		return "encoder"
+	}
+	if handler == slog.DiscardHandler {
+		return "discard"
+	}
+	return fmt.Sprintf("%T", handler)
+}
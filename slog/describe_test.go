@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDescribeHandler(t *testing.T) {
+	// Save original environment variables and default logger
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	clearEnvVars()
+	t.Setenv(EnvLoggerHandler, "json")
+	t.Setenv(EnvLoggerAddGoID, "true")
+	t.Setenv(EnvLoggerMaxAttrs, "10")
+	t.Setenv(EnvLoggerSampleAdaptive, "true")
+	t.Setenv(EnvLoggerSampleTarget, "100")
+	Init()
+
+	expected := []string{"context", "swappable", "sampler", "goid", "max_attrs", "output", "json"}
+	if got := DescribeHandler(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// The description follows reconfiguration
+	clearEnvVars()
+	t.Setenv(EnvLoggerHandler, "text")
+	Init()
+
+	expected = []string{"context", "swappable", "output", "text"}
+	if got := DescribeHandler(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestDescribeHandlerChains(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  slog.Handler
+		expected []string
+	}{
+		{
+			name:     "strict context",
+			handler:  createHandler(&Config{HandlerType: "text", ContextStrict: true}, os.Stderr),
+			expected: []string{"context(strict)", "output", "text"},
+		},
+		{
+			name:     "discard",
+			handler:  createHandler(&Config{HandlerType: "discard"}, os.Stderr),
+			expected: []string{"discard"},
+		},
+		{
+			name: "multi",
+			handler: NewMultiHandler(
+				createBaseHandler(&Config{HandlerType: "text", AddGoID: true}, os.Stderr),
+				slog.NewJSONHandler(os.Stderr, nil),
+			),
+			expected: []string{"multi(goid > output > text, json)"},
+		},
+		{
+			name:     "foreign handler",
+			handler:  newContextAwareHandler(&wrapperHandler{next: slog.DiscardHandler}),
+			expected: []string{"context", "*slog.wrapperHandler"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeHandler(tt.handler); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
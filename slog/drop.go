@@ -0,0 +1,39 @@
+package slog
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// Reasons passed to the drop callback.
+const (
+	DropReasonSampled   = "sampled"
+	DropReasonQueueFull = "queue_full"
+	DropReasonTimeout   = "timeout"
+	DropReasonTooLarge  = "too_large"
+)
+
+var dropCallback atomic.Pointer[func(r slog.Record, reason string)]
+
+// SetDropCallback sets a function that is called whenever a handler wrapper
+// drops a record instead of writing it, with the reason for the drop (one of
+// the DropReason constants). Passing nil removes the callback.
+//
+// The callback is called synchronously on the logging goroutine, possibly from
+// several goroutines at once, so it must be cheap and safe for concurrent use,
+// for example incrementing a counter. It must not log through the logger that
+// dropped the record.
+func SetDropCallback(fn func(r slog.Record, reason string)) {
+	if fn == nil {
+		dropCallback.Store(nil)
+		return
+	}
+	dropCallback.Store(&fn)
+}
+
+// reportDrop notifies the drop callback, if any, that r was dropped.
+func reportDrop(r slog.Record, reason string) {
+	if fn := dropCallback.Load(); fn != nil {
+		(*fn)(r, reason)
+	}
+}
@@ -0,0 +1,82 @@
+package slog
+
+import (
+	"io"
+	"log/slog"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetDropCallback(t *testing.T) {
+	defer SetDropCallback(nil)
+
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	SetDropCallback(func(r slog.Record, reason string) {
+		mu.Lock()
+		defer mu.Unlock()
+		counts[reason]++
+	})
+
+	// The sampler drops records above its target rate
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := newAdaptiveSampler(1)
+	sampler.now = func() time.Time { return now }
+	sampler.rand = func() float64 { return 0.99 }
+	sampled := slog.New(newSamplerHandler(slog.NewTextHandler(io.Discard, nil), sampler))
+	sampled.Info("kept")
+	sampled.Info("sampled")
+
+	// The early capture buffer drops records beyond its limit
+	early := slog.New(&earlyHandler{buf: &earlyBuffer{limit: 1}})
+	early.Info("buffered")
+	early.Info("queue full")
+
+	// The timeout handler drops records whose write does not complete in time
+	w := newBlockingWriter()
+	defer w.release()
+	slog.New(newTimeoutHandler(slog.NewTextHandler(w, nil), 10*time.Millisecond)).Info("timeout")
+
+	// The max line writer drops lines that are too long with the drop action
+	tooLarge := slog.New(slog.NewTextHandler(newMaxLineWriter(io.Discard, 16, "drop"), nil))
+	tooLarge.Info("too large")
+
+	mu.Lock()
+	want := map[string]int{
+		DropReasonSampled:   1,
+		DropReasonQueueFull: 1,
+		DropReasonTimeout:   1,
+		DropReasonTooLarge:  1,
+	}
+	if !maps.Equal(counts, want) {
+		t.Errorf("expected drops %v, got %v", want, counts)
+	}
+	mu.Unlock()
+
+	SetDropCallback(nil)
+	sampled.Info("sampled")
+	mu.Lock()
+	defer mu.Unlock()
+	if counts[DropReasonSampled] != 1 {
+		t.Errorf("expected the callback to be removed, got %d drops", counts[DropReasonSampled])
+	}
+}
+
+func TestReportDropRecord(t *testing.T) {
+	defer SetDropCallback(nil)
+
+	var got slog.Record
+	SetDropCallback(func(r slog.Record, reason string) {
+		got = r
+	})
+
+	r := slog.NewRecord(time.Now(), slog.LevelWarn, "dropped", 0)
+	r.AddAttrs(slog.String("k", "v"))
+	reportDrop(r, DropReasonQueueFull)
+
+	if got.Message != "dropped" || got.Level != slog.LevelWarn || got.NumAttrs() != 1 {
+		t.Errorf("unexpected record passed to callback: %v", got)
+	}
+}
@@ -0,0 +1,178 @@
+package slog
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"sync"
+)
+
+// DefaultEarlyCaptureLimit is the default maximum number of records buffered
+// by CaptureEarly.
+const DefaultEarlyCaptureLimit = 1000
+
+var (
+	// earlyMu guards earlyCapture.
+	earlyMu sync.Mutex
+	// earlyCapture is the buffer installed by CaptureEarly until Init releases it.
+	earlyCapture *earlyBuffer
+)
+
+// earlyBuffer holds the records logged through the default logger installed
+// by CaptureEarly.
+type earlyBuffer struct {
+	mu      sync.Mutex
+	limit   int
+	records []earlyRecord
+	dropped int
+	// target is the handler records are forwarded to once the buffer has
+	// been released, or nil while capturing.
+	target slog.Handler
+
+	// previous is the default logger replaced by CaptureEarly, and logWriter
+	// and logFlags the log package settings it replaced along with it.
+	previous  *slog.Logger
+	logWriter io.Writer
+	logFlags  int
+}
+
+// earlyRecord is a buffered record and the operations of the logger it was
+// logged through.
+type earlyRecord struct {
+	ops []handlerOp
+	r   slog.Record
+}
+
+// CaptureEarly installs a default logger that buffers its records until Init
+// configures the package, so that logs made before Init runs, for example in
+// the init functions of other packages, reach the configured destination.
+// Importing the early package calls it as early as package initialization
+// allows:
+//
+//	import _ "github.com/nakat-t/planks-go/slog/early"
+//
+// Up to limit records are buffered (DefaultEarlyCaptureLimit if limit is not
+// positive); further records are dropped and reported to the drop callback
+// with DropReasonQueueFull, and their number is logged as a warning on replay.
+// Records are buffered regardless of their level and filtered by the
+// configured level on replay.
+//
+// Init replays the buffered records, in the order they were logged, through
+// the default logger it installed, before it returns and before any records
+// logged meanwhile through loggers derived from the capturing default logger,
+// which then forward to the new default logger. If Init does not install a
+// default logger, because no logger variable is set or the configuration is
+// invalid, the previous default logger is restored and receives the records.
+// Records are replayed without their context, so context loggers are not
+// honored. Calling CaptureEarly again before Init has no effect.
+func CaptureEarly(limit int) {
+	if limit <= 0 {
+		limit = DefaultEarlyCaptureLimit
+	}
+
+	earlyMu.Lock()
+	defer earlyMu.Unlock()
+	if earlyCapture != nil {
+		return
+	}
+	buf := &earlyBuffer{
+		limit:     limit,
+		previous:  slog.Default(),
+		logWriter: log.Writer(),
+		logFlags:  log.Flags(),
+	}
+	earlyCapture = buf
+	slog.SetDefault(slog.New(&earlyHandler{buf: buf}))
+}
+
+// releaseEarly replays the records buffered by CaptureEarly, if any, through
+// the current default logger and makes the capturing handlers forward to it.
+// If the capturing default logger is still installed, the previous default
+// logger is restored first.
+func releaseEarly() {
+	earlyMu.Lock()
+	defer earlyMu.Unlock()
+	buf := earlyCapture
+	if buf == nil {
+		return
+	}
+	earlyCapture = nil
+
+	if h, ok := slog.Default().Handler().(*earlyHandler); ok && h.buf == buf {
+		slog.SetDefault(buf.previous)
+		// Restoring the standard default logger does not undo the
+		// redirection of the log package to the capturing handler.
+		log.SetOutput(buf.logWriter)
+		log.SetFlags(buf.logFlags)
+	}
+	target := slog.Default().Handler()
+
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+	ctx := context.Background()
+	for _, er := range buf.records {
+		if h := applyHandlerOps(target, er.ops); h.Enabled(ctx, er.r.Level) {
+			_ = h.Handle(ctx, er.r)
+		}
+	}
+	if buf.dropped > 0 {
+		slog.New(target).Warn("dropped early log records", "count", buf.dropped)
+	}
+	buf.records = nil
+	buf.target = target
+}
+
+// earlyHandler is the handler of the default logger installed by
+// CaptureEarly. It buffers records until the buffer is released and forwards
+// them to the buffer's target afterwards.
+type earlyHandler struct {
+	buf *earlyBuffer
+	ops []handlerOp
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *earlyHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	h.buf.mu.Lock()
+	target := h.buf.target
+	h.buf.mu.Unlock()
+	if target != nil {
+		return applyHandlerOps(target, h.ops).Enabled(ctx, level)
+	}
+	return true
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *earlyHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.buf.mu.Lock()
+	if target := h.buf.target; target != nil {
+		h.buf.mu.Unlock()
+		return applyHandlerOps(target, h.ops).Handle(ctx, r)
+	}
+	defer h.buf.mu.Unlock()
+
+	if len(h.buf.records) >= h.buf.limit {
+		h.buf.dropped++
+		reportDrop(r, DropReasonQueueFull)
+		return nil
+	}
+	h.buf.records = append(h.buf.records, earlyRecord{ops: h.ops, r: r.Clone()})
+	return nil
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *earlyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(handlerOp{attrs: attrs})
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *earlyHandler) WithGroup(name string) slog.Handler {
+	return h.derive(handlerOp{group: name})
+}
+
+// derive returns a handler with op appended to h's operations.
+func (h *earlyHandler) derive(op handlerOp) *earlyHandler {
+	ops := make([]handlerOp, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &earlyHandler{buf: h.buf, ops: append(ops, op)}
+}
@@ -0,0 +1,21 @@
+// Package early buffers the logs made before the planks-go/slog package is
+// configured. Importing this package installs a default logger that buffers
+// its records until slog.Init (or the init package) configures the default
+// logger, which then replays them:
+//
+//	import (
+//		_ "github.com/nakat-t/planks-go/slog/early"
+//		_ "github.com/nakat-t/planks-go/slog/init"
+//	)
+//
+// Only the logs of packages initialized after this one are captured. See
+// slog.CaptureEarly for the limits and ordering guarantees.
+package early
+
+import (
+	"github.com/nakat-t/planks-go/slog"
+)
+
+func init() {
+	slog.CaptureEarly(slog.DefaultEarlyCaptureLimit)
+}
@@ -0,0 +1,132 @@
+package slog
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCaptureEarly(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	defer Close()
+	defer SetDropCallback(nil)
+
+	var drops int
+	SetDropCallback(func(r slog.Record, reason string) {
+		if reason == DropReasonQueueFull {
+			drops++
+		}
+	})
+
+	// Simulate logging in package initialization before Init runs
+	CaptureEarly(3)
+	early := slog.Default().With("component", "db").WithGroup("g")
+	slog.Debug("first")
+	early.Info("second", "k", 1)
+	slog.Warn("third")
+	slog.Info("fourth")
+
+	path := filepath.Join(t.TempDir(), "test.log")
+	clearEnvVars()
+	t.Setenv(EnvLoggerLevel, "info")
+	t.Setenv(EnvLoggerHandler, "bare")
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, path)
+	Init()
+
+	// Loggers derived before Init forward to the configured logger
+	early.Info("fifth", "k", 2)
+	slog.Info("sixth")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	expected := "msg=second component=db g.k=1\n" +
+		"msg=third\n" +
+		"msg=\"dropped early log records\" count=1\n" +
+		"msg=fifth component=db g.k=2\n" +
+		"msg=sixth\n"
+	if string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, data)
+	}
+	if drops != 1 {
+		t.Errorf("expected one drop, got %d", drops)
+	}
+}
+
+func TestCaptureEarlyWithoutConfig(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	var buf bytes.Buffer
+	previous := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: removeTime,
+	}))
+	slog.SetDefault(previous)
+	logWriter, logFlags := log.Writer(), log.Flags()
+
+	CaptureEarly(0)
+	slog.Info("early")
+
+	// Init without any logger variable restores the previous default
+	clearEnvVars()
+	Init()
+
+	if slog.Default() != previous {
+		t.Errorf("expected the previous default logger to be restored")
+	}
+	if log.Writer() != logWriter || log.Flags() != logFlags {
+		t.Errorf("expected the log package settings to be restored")
+	}
+	if expected := "level=INFO msg=early\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+// removeTime is a ReplaceAttr function that removes the time attribute.
+func removeTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
+
+func TestCaptureEarlyStandardDefault(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	logWriter, logFlags := log.Writer(), log.Flags()
+	defer func() {
+		log.SetOutput(logWriter)
+		log.SetFlags(logFlags)
+	}()
+
+	// Replaying into the standard default logger must not loop back through
+	// the log package
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	slog.SetDefault(slog.New(slog.Default().Handler()))
+
+	CaptureEarly(0)
+	slog.Info("early")
+	log.Print("from log")
+
+	clearEnvVars()
+	Init()
+
+	if out := buf.String(); !strings.Contains(out, "INFO early") || !strings.Contains(out, "INFO from log") {
+		t.Errorf("unexpected output %q", out)
+	}
+}
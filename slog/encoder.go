@@ -0,0 +1,172 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Encoder serializes records for BuildWithEncoder, for example as
+// MessagePack or CBOR.
+//
+// Encode writes the encoding of r to w. The record holds every attribute of
+// the logger, including those added with With and WithGroup, nested in their
+// groups, with values resolved and LOGGER_* attribute rewrites such as
+// LOGGER_HASH_KEYS applied. The built-in time, level, message and PC are the
+// record's fields. Encode is called for one record at a time, and w collects
+// the encoding so that it is written to the destination with a single call.
+//
+// The rewrites of the time and message, such as LOGGER_TIMEZONE and
+// LOGGER_MASK_PATTERNS, are applied to the record's fields as well. The time
+// is zero if a setting drops it, as LOGGER_HANDLER=bare does.
+type Encoder interface {
+	Encode(w io.Writer, r slog.Record) error
+}
+
+// BuildWithEncoder creates a logger based on environment variables like
+// Build, but serializes records with enc instead of the handler selected by
+// LOGGER_HANDLER, which is ignored unless it is "discard". The level, writer,
+// handler wrappers and context-aware handling are configured as usual.
+// If no relevant environment variables are set, it returns
+// (nil, ErrNoEnvVarSet).
+func BuildWithEncoder(enc Encoder) (*slog.Logger, error) {
+	config, err := ReadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		config.encoder = enc
+	}
+	return buildLogger(config)
+}
+
+// encoderHandler is a handler that serializes records with an Encoder.
+type encoderHandler struct {
+	enc         Encoder
+	w           io.Writer
+	mu          *sync.Mutex // serializes writes to w
+	level       slog.Leveler
+	replaceAttr func([]string, slog.Attr) slog.Attr
+	ops         []handlerOp
+}
+
+// newEncoderHandler creates a new handler that writes records encoded with enc to w.
+func newEncoderHandler(enc Encoder, w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	return &encoderHandler{
+		enc:         enc,
+		w:           w,
+		mu:          new(sync.Mutex),
+		level:       opts.Level,
+		replaceAttr: opts.ReplaceAttr,
+	}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *encoderHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *encoderHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	// Nest the record's attributes in the open groups, innermost first,
+	// preceded by the attributes added at each level.
+	for i := len(h.ops) - 1; i >= 0; i-- {
+		if op := h.ops[i]; op.attrs != nil {
+			attrs = append(append([]slog.Attr(nil), op.attrs...), attrs...)
+		} else if len(attrs) > 0 {
+			attrs = []slog.Attr{{Key: op.group, Value: slog.GroupValue(attrs...)}}
+		}
+	}
+
+	t, msg := r.Time, r.Message
+	if h.replaceAttr != nil {
+		if !t.IsZero() {
+			a := h.replaceAttr(nil, slog.Time(slog.TimeKey, t))
+			if a.Value = a.Value.Resolve(); a.Value.Kind() == slog.KindTime {
+				t = a.Value.Time()
+			} else if a.Equal(slog.Attr{}) {
+				t = time.Time{}
+			}
+		}
+		a := h.replaceAttr(nil, slog.String(slog.MessageKey, msg))
+		if a.Value = a.Value.Resolve(); a.Value.Kind() == slog.KindString {
+			msg = a.Value.String()
+		}
+	}
+
+	encoded := slog.NewRecord(t, r.Level, msg, r.PC)
+	encoded.AddAttrs(h.prepareAttrs(nil, attrs)...)
+
+	var buf bytes.Buffer
+	if err := h.enc.Encode(&buf, encoded); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// prepareAttrs resolves the values of attrs, applies the ReplaceAttr function
+// and drops empty attributes, recursing into groups as the built-in handlers do.
+func (h *encoderHandler) prepareAttrs(groups []string, attrs []slog.Attr) []slog.Attr {
+	prepared := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			inner := a.Value.Group()
+			if a.Key != "" {
+				inner = h.prepareAttrs(append(groups[:len(groups):len(groups)], a.Key), inner)
+				if len(inner) > 0 {
+					prepared = append(prepared, slog.Attr{Key: a.Key, Value: slog.GroupValue(inner...)})
+				}
+			} else {
+				// Groups without a key are inlined.
+				prepared = append(prepared, h.prepareAttrs(groups, inner)...)
+			}
+			continue
+		}
+		if h.replaceAttr != nil {
+			a = h.replaceAttr(groups, a)
+			a.Value = a.Value.Resolve()
+		}
+		if !a.Equal(slog.Attr{}) {
+			prepared = append(prepared, a)
+		}
+	}
+	return prepared
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *encoderHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.derive(handlerOp{attrs: attrs})
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *encoderHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.derive(handlerOp{group: name})
+}
+
+// derive returns a handler with op appended to h's operations.
+func (h *encoderHandler) derive(op handlerOp) *encoderHandler {
+	ops := make([]handlerOp, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	derived := *h
+	derived.ops = append(ops, op)
+	return &derived
+}
@@ -0,0 +1,220 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// cborishEncoder is a CBOR-like test encoder. A record is encoded as a
+// length-prefixed map of text keys to values, where a value is either a
+// text string or a nested map. The time is omitted to keep records stable.
+type cborishEncoder struct{}
+
+const (
+	cborishText byte = 0x60
+	cborishMap  byte = 0xa0
+)
+
+func (cborishEncoder) Encode(w io.Writer, r slog.Record) error {
+	attrs := []slog.Attr{slog.String("level", r.Level.String()), slog.String("msg", r.Message)}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	var body bytes.Buffer
+	encodeCborishMap(&body, attrs)
+	if err := binary.Write(w, binary.BigEndian, uint32(body.Len())); err != nil {
+		return err
+	}
+	_, err := w.Write(body.Bytes())
+	return err
+}
+
+func encodeCborishMap(b *bytes.Buffer, attrs []slog.Attr) {
+	b.WriteByte(cborishMap)
+	b.WriteByte(byte(len(attrs)))
+	for _, a := range attrs {
+		encodeCborishText(b, a.Key)
+		if a.Value.Kind() == slog.KindGroup {
+			encodeCborishMap(b, a.Value.Group())
+		} else {
+			encodeCborishText(b, a.Value.String())
+		}
+	}
+}
+
+func encodeCborishText(b *bytes.Buffer, s string) {
+	b.WriteByte(cborishText)
+	b.WriteByte(byte(len(s)))
+	b.WriteString(s)
+}
+
+// decodeCborish decodes the records in data into maps of strings and nested maps.
+func decodeCborish(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for len(data) > 0 {
+		if len(data) < 4 {
+			t.Fatalf("truncated record header: %x", data)
+		}
+		n := binary.BigEndian.Uint32(data)
+		if len(data) < 4+int(n) {
+			t.Fatalf("truncated record: %x", data)
+		}
+		body := bytes.NewReader(data[4 : 4+n])
+		records = append(records, decodeCborishMap(t, body))
+		if body.Len() != 0 {
+			t.Fatalf("trailing bytes in record: %d", body.Len())
+		}
+		data = data[4+n:]
+	}
+	return records
+}
+
+func decodeCborishMap(t *testing.T, r *bytes.Reader) map[string]any {
+	t.Helper()
+	if tag, _ := r.ReadByte(); tag != cborishMap {
+		t.Fatalf("expected a map, got tag %#x", tag)
+	}
+	n, _ := r.ReadByte()
+	m := make(map[string]any, n)
+	for i := 0; i < int(n); i++ {
+		key := decodeCborishText(t, r)
+		if tag, _ := r.ReadByte(); tag == cborishMap {
+			r.UnreadByte()
+			m[key] = decodeCborishMap(t, r)
+		} else {
+			r.UnreadByte()
+			m[key] = decodeCborishText(t, r)
+		}
+	}
+	return m
+}
+
+func decodeCborishText(t *testing.T, r *bytes.Reader) string {
+	t.Helper()
+	if tag, _ := r.ReadByte(); tag != cborishText {
+		t.Fatalf("expected a text string, got tag %#x", tag)
+	}
+	n, _ := r.ReadByte()
+	s := make([]byte, n)
+	if _, err := io.ReadFull(r, s); err != nil {
+		t.Fatalf("truncated text string: %v", err)
+	}
+	return string(s)
+}
+
+func TestBuildWithEncoder(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	clearEnvVars()
+	logPath := filepath.Join(t.TempDir(), "test.log")
+	t.Setenv(EnvLoggerLevel, "info")
+	t.Setenv(EnvLoggerHandler, "json")
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, logPath)
+
+	logger, err := BuildWithEncoder(cborishEncoder{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := describeHandler(logger.Handler()); len(names) == 0 || names[len(names)-1] != "encoder" {
+		t.Errorf("expected the chain to end in the encoder handler, got %v", names)
+	}
+
+	logger.Debug("hidden")
+	logger.With("service", "api").WithGroup("req").With("id", 7).WithGroup("empty").Info("hello", "n", 1)
+	logger.WithGroup("g").Info("bare", slog.Group("", slog.String("inline", "yes")), slog.Group("none"))
+	logger.InfoContext(context.Background(), "resolved", "v", slog.AnyValue(testLogValuer{"secret"}))
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	got := decodeCborish(t, data)
+	want := []map[string]any{
+		{"level": "INFO", "msg": "hello", "service": "api", "req": map[string]any{"id": "7", "empty": map[string]any{"n": "1"}}},
+		{"level": "INFO", "msg": "bare", "g": map[string]any{"inline": "yes"}},
+		{"level": "INFO", "msg": "resolved", "v": "resolved:secret"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected records:\n got  %v\n want %v", got, want)
+	}
+}
+
+func TestBuildWithEncoder_ReplaceAttr(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	clearEnvVars()
+	logPath := filepath.Join(t.TempDir(), "test.log")
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, logPath)
+	t.Setenv(EnvLoggerRenameKeys, "g.user=account")
+
+	logger, err := BuildWithEncoder(cborishEncoder{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.WithGroup("g").Info("hello", "user", "bob")
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	got := decodeCborish(t, data)
+	want := []map[string]any{{"level": "INFO", "msg": "hello", "g": map[string]any{"account": "bob"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected records:\n got  %v\n want %v", got, want)
+	}
+}
+
+func TestBuildWithEncoder_Error(t *testing.T) {
+	errEncode := errors.New("encode failed")
+	var buf bytes.Buffer
+	h := newEncoderHandler(encoderFunc(func(w io.Writer, r slog.Record) error {
+		fmt.Fprint(w, "partial")
+		return errEncode
+	}), &buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, "hello", 0)
+	if err := h.Handle(context.Background(), r); !errors.Is(err, errEncode) {
+		t.Errorf("expected the encoder error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written on encoder errors, got %q", buf.String())
+	}
+}
+
+func TestBuildWithEncoder_NoEnvVars(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	clearEnvVars()
+	if _, err := BuildWithEncoder(cborishEncoder{}); !errors.Is(err, ErrNoEnvVarSet) {
+		t.Errorf("expected ErrNoEnvVarSet, got %v", err)
+	}
+}
+
+// encoderFunc adapts a function to the Encoder interface.
+type encoderFunc func(w io.Writer, r slog.Record) error
+
+func (f encoderFunc) Encode(w io.Writer, r slog.Record) error { return f(w, r) }
+
+// testLogValuer is a LogValuer whose value is resolved by the handler.
+type testLogValuer struct{ s string }
+
+func (v testLogValuer) LogValue() slog.Value { return slog.StringValue("resolved:" + v.s) }
@@ -0,0 +1,26 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// parseAttrsFromEnv parses a comma-separated list of "key=ENV_VAR" pairs and
+// returns an attribute for each pair whose environment variable is set, in
+// list order.
+func parseAttrsFromEnv(s string) ([]slog.Attr, error) {
+	var attrs []slog.Attr
+	for _, pair := range splitList(s) {
+		key, envVar, ok := strings.Cut(pair, "=")
+		key, envVar = strings.TrimSpace(key), strings.TrimSpace(envVar)
+		if !ok || key == "" || envVar == "" {
+			return nil, fmt.Errorf("%w: %q: want key=ENV_VAR", ErrInvalidAttrsFromEnv, pair)
+		}
+		if value := os.Getenv(envVar); value != "" {
+			attrs = append(attrs, slog.String(key, value))
+		}
+	}
+	return attrs, nil
+}
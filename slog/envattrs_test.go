@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestParseAttrsFromEnv(t *testing.T) {
+	t.Setenv("TEST_HOSTNAME", "web-1")
+	t.Setenv("TEST_POD_NAME", "pod-a")
+	t.Setenv("TEST_UNSET", "")
+
+	attrs, err := parseAttrsFromEnv("host=TEST_HOSTNAME, missing=TEST_UNSET, pod=TEST_POD_NAME")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []slog.Attr{slog.String("host", "web-1"), slog.String("pod", "pod-a")}
+	if len(attrs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, attrs)
+	}
+	for i := range expected {
+		if !attrs[i].Equal(expected[i]) {
+			t.Errorf("attr %d: expected %v, got %v", i, expected[i], attrs[i])
+		}
+	}
+}
+
+func TestParseAttrsFromEnvInvalid(t *testing.T) {
+	for _, s := range []string{"host", "=HOSTNAME", "host=", "host=HOSTNAME,bad"} {
+		if _, err := parseAttrsFromEnv(s); !errors.Is(err, ErrInvalidAttrsFromEnv) {
+			t.Errorf("%q: expected ErrInvalidAttrsFromEnv, got %v", s, err)
+		}
+	}
+}
+
+func TestAttrsFromEnv(t *testing.T) {
+	t.Setenv("TEST_HOSTNAME", "web-1")
+	t.Setenv("TEST_POD_NAME", "")
+	config, err := readConfigFromEnv(t, map[string]string{
+		EnvLoggerAttrsFromEnv: "host=TEST_HOSTNAME,pod=TEST_POD_NAME",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config.HandlerType = "bare"
+
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(config, &buf))
+	logger.Info("test", "k", "v")
+
+	if expected := "msg=test host=web-1 k=v\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
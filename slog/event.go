@@ -0,0 +1,116 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// eventQueueSize is the number of matched records that may wait for their
+// event handlers before further events are discarded.
+const eventQueueSize = 256
+
+// eventMatcher pairs a record predicate with the handler of matching records.
+type eventMatcher struct {
+	match   func(slog.Record) bool
+	handler func(slog.Record)
+}
+
+var (
+	eventMatchersMu sync.RWMutex
+	eventMatchers   []eventMatcher
+
+	eventQueueOnce sync.Once
+	eventQueue     chan func()
+)
+
+// RegisterEventMatcher registers a log-to-event bridge: every record for which
+// match returns true is also passed to handler, in addition to being logged
+// as usual, for example to raise an alert on high-signal messages.
+//
+// match is called on the logging goroutine and must be cheap and safe for
+// concurrent use. handler is called later on a separate goroutine, one event
+// at a time, so slow handlers do not block logging; if too many events are
+// waiting, further events are discarded. The record passed to both holds the
+// attributes of the logging call, but not those added with With.
+//
+// Matchers are applied by the loggers built after they are registered, so
+// register them before calling Init or Build. Records below the logger's
+// level never match, but records dropped by sampling do.
+func RegisterEventMatcher(match func(slog.Record) bool, handler func(slog.Record)) {
+	eventMatchersMu.Lock()
+	defer eventMatchersMu.Unlock()
+	eventMatchers = append(eventMatchers, eventMatcher{match: match, handler: handler})
+}
+
+// registeredEventMatchers returns a copy of the registered event matchers.
+func registeredEventMatchers() []eventMatcher {
+	eventMatchersMu.RLock()
+	defer eventMatchersMu.RUnlock()
+	return append([]eventMatcher(nil), eventMatchers...)
+}
+
+// dispatchEvent queues fn to run on the event goroutine, discarding it if the
+// queue is full.
+func dispatchEvent(fn func()) {
+	eventQueueOnce.Do(func() {
+		eventQueue = make(chan func(), eventQueueSize)
+		go func() {
+			for fn := range eventQueue {
+				fn()
+			}
+		}()
+	})
+	select {
+	case eventQueue <- fn:
+	default:
+	}
+}
+
+// eventHandler is a wrapper handler that passes the records matched by the
+// event matchers to their event handlers.
+type eventHandler struct {
+	next     slog.Handler
+	matchers []eventMatcher
+}
+
+// newEventHandler creates a new handler that fires the events of matchers.
+func newEventHandler(next slog.Handler, matchers []eventMatcher) slog.Handler {
+	return &eventHandler{next: next, matchers: matchers}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *eventHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *eventHandler) Handle(ctx context.Context, r slog.Record) error {
+	for _, m := range h.matchers {
+		if m.match(r) {
+			handler, event := m.handler, r.Clone()
+			dispatchEvent(func() { handler(event) })
+		}
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *eventHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &eventHandler{next: h.next.WithAttrs(attrs), matchers: h.matchers}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *eventHandler) WithGroup(name string) slog.Handler {
+	return &eventHandler{next: h.next.WithGroup(name), matchers: h.matchers}
+}
+
+// describe implements describer.
+func (h *eventHandler) describe() string {
+	return "event"
+}
+
+// unwrap implements describer.
+func (h *eventHandler) unwrap() slog.Handler {
+	return h.next
+}
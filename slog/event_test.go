@@ -0,0 +1,105 @@
+package slog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetEventMatchers removes all registered event matchers.
+func resetEventMatchers() {
+	eventMatchersMu.Lock()
+	defer eventMatchersMu.Unlock()
+	eventMatchers = nil
+}
+
+// hasAttr reports whether r has an attribute with the given key and value.
+func hasAttr(r slog.Record, key, value string) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = a.Key == key && a.Value.String() == value
+		return !found
+	})
+	return found
+}
+
+func TestRegisterEventMatcher(t *testing.T) {
+	defer resetEventMatchers()
+
+	events := make(chan slog.Record, 10)
+	RegisterEventMatcher(func(r slog.Record) bool {
+		return r.Message == "payment failed" || hasAttr(r, "alert", "true")
+	}, func(r slog.Record) {
+		events <- r
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare"}, &buf))
+	logger.Info("payment ok")
+	logger.Debug("payment failed")
+	logger.Error("payment failed", "order", "A-1")
+	logger.With("alert", "true").Warn("disk full")
+	logger.Warn("disk full", "alert", "true")
+
+	if got := strings.Count(buf.String(), "\n"); got != 4 {
+		t.Errorf("expected every enabled record to be logged, got %q", buf.String())
+	}
+	for _, want := range []string{"payment failed", "disk full"} {
+		select {
+		case r := <-events:
+			if r.Message != want {
+				t.Errorf("expected an event for %q, got %q", want, r.Message)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for the event for %q", want)
+		}
+	}
+	select {
+	case r := <-events:
+		t.Errorf("unexpected event for %q", r.Message)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestRegisterEventMatcherDoesNotBlock(t *testing.T) {
+	defer resetEventMatchers()
+
+	release := make(chan struct{})
+	defer close(release)
+	RegisterEventMatcher(func(slog.Record) bool { return true }, func(slog.Record) {
+		<-release
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare"}, &buf))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 2*eventQueueSize; i++ {
+			logger.Info("event")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected logging to continue while an event handler blocks")
+	}
+}
+
+func TestRegisterEventMatcherAfterBuild(t *testing.T) {
+	defer resetEventMatchers()
+
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare"}, &buf))
+	fired := make(chan struct{}, 1)
+	RegisterEventMatcher(func(slog.Record) bool { return true }, func(slog.Record) { fired <- struct{}{} })
+
+	logger.Info("test")
+	select {
+	case <-fired:
+		t.Errorf("expected matchers registered after build to be ignored")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
@@ -0,0 +1,503 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// fastJSONHandler is a JSON handler for LOGGER_JSON_FAST that encodes the
+// values of the common kinds (strings, integers, floats, booleans, durations
+// and times) without going through encoding/json, producing the same output
+// as slog.JSONHandler. A record holding a value of another kind, such as an
+// error or a struct, is handled by the standard JSON handler instead, as are
+// all records of a handler derived with such a value in WithAttrs.
+//
+// The layout of the output, including the handling of groups, ReplaceAttr and
+// empty attributes, follows slog.JSONHandler, except that a group whose
+// attributes are all removed by ReplaceAttr does not leave the separator of
+// the next attribute out.
+type fastJSONHandler struct {
+	std          slog.Handler // handles the records the fast path does not support
+	w            io.Writer
+	mu           *sync.Mutex // serializes writes to w with those of std
+	level        slog.Leveler
+	addSource    bool
+	replaceAttr  func([]string, slog.Attr) slog.Attr
+	preformatted []byte   // attributes added with WithAttrs
+	groups       []string // groups opened with WithGroup
+	nOpenGroups  int      // number of groups opened in preformatted
+	slow         bool     // std handles every record
+}
+
+// newFastJSONHandler creates a new fast JSON handler that writes to w.
+func newFastJSONHandler(w io.Writer, opts *slog.HandlerOptions) slog.Handler {
+	mu := new(sync.Mutex)
+	return &fastJSONHandler{
+		std:         slog.NewJSONHandler(&lockedWriter{mu: mu, w: w}, opts),
+		w:           w,
+		mu:          mu,
+		level:       opts.Level,
+		addSource:   opts.AddSource,
+		replaceAttr: opts.ReplaceAttr,
+	}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *fastJSONHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.level != nil {
+		minLevel = h.level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *fastJSONHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.slow {
+		return h.std.Handle(ctx, r)
+	}
+
+	bufp := fastJSONBufPool.Get().(*[]byte)
+	s := h.newState((*bufp)[:0], "")
+	defer func() {
+		// Large buffers are not kept so that one huge record does not pin
+		// its memory.
+		if cap(s.buf) <= 64<<10 {
+			*bufp = s.buf[:0]
+			fastJSONBufPool.Put(bufp)
+		}
+	}()
+	s.buf = append(s.buf, '{')
+
+	// Built-in attributes are not in a group.
+	groups := s.groups
+	s.groups = nil
+	if !r.Time.IsZero() {
+		t := r.Time.Round(0)
+		if h.replaceAttr == nil {
+			s.appendKey(slog.TimeKey)
+			s.appendTime(t)
+		} else {
+			s.appendAttr(slog.Time(slog.TimeKey, t))
+		}
+	}
+	if h.replaceAttr == nil {
+		s.appendKey(slog.LevelKey)
+		s.appendString(r.Level.String())
+	} else {
+		s.appendAttr(slog.Any(slog.LevelKey, r.Level))
+	}
+	if h.addSource {
+		s.appendAttr(slog.Any(slog.SourceKey, recordSource(r.PC)))
+	}
+	if h.replaceAttr == nil {
+		s.appendKey(slog.MessageKey)
+		s.appendString(r.Message)
+	} else {
+		s.appendAttr(slog.String(slog.MessageKey, r.Message))
+	}
+	s.groups = groups
+
+	if len(h.preformatted) > 0 {
+		s.buf = append(s.buf, s.sep...)
+		s.buf = append(s.buf, h.preformatted...)
+		s.sep = ","
+		if h.preformatted[len(h.preformatted)-1] == '{' {
+			s.sep = ""
+		}
+	}
+	// If the record has no attributes, the groups are not output.
+	nOpenGroups := h.nOpenGroups
+	if r.NumAttrs() > 0 {
+		pos := len(s.buf)
+		s.openGroups()
+		nOpenGroups = len(h.groups)
+		empty := true
+		r.Attrs(func(a slog.Attr) bool {
+			if s.appendAttr(a) {
+				empty = false
+			}
+			return s.ok
+		})
+		if empty {
+			s.buf = s.buf[:pos]
+			nOpenGroups = h.nOpenGroups
+		}
+	}
+	for range nOpenGroups {
+		s.buf = append(s.buf, '}')
+	}
+	s.buf = append(s.buf, "}\n"...)
+
+	if !s.ok {
+		return h.std.Handle(ctx, r)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(s.buf)
+	return err
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *fastJSONHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	empty := 0
+	for _, a := range attrs {
+		if a.Value.Kind() == slog.KindGroup && len(a.Value.Group()) == 0 {
+			empty++
+		}
+	}
+	if empty == len(attrs) {
+		return h
+	}
+
+	h2 := h.clone()
+	h2.std = h.std.WithAttrs(attrs)
+	if h.slow {
+		return h2
+	}
+	sep := ""
+	if len(h.preformatted) > 0 && h.preformatted[len(h.preformatted)-1] != '{' {
+		sep = ","
+	}
+	s := h.newState(h2.preformatted, sep)
+	pos := len(s.buf)
+	s.openGroups()
+	nonEmpty := false
+	for _, a := range attrs {
+		if s.appendAttr(a) {
+			nonEmpty = true
+		}
+	}
+	switch {
+	case !s.ok:
+		h2.slow = true
+	case !nonEmpty:
+		h2.preformatted = s.buf[:pos]
+	default:
+		h2.preformatted = s.buf
+		h2.nOpenGroups = len(h2.groups)
+	}
+	return h2
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *fastJSONHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := h.clone()
+	h2.std = h.std.WithGroup(name)
+	h2.groups = append(h2.groups, name)
+	return h2
+}
+
+// clone returns a copy of h that can be extended without affecting h.
+func (h *fastJSONHandler) clone() *fastJSONHandler {
+	h2 := *h
+	h2.preformatted = slices.Clip(h.preformatted)
+	h2.groups = slices.Clip(h.groups)
+	return &h2
+}
+
+// newState returns the encoding state for appending to buf with the given
+// initial separator.
+func (h *fastJSONHandler) newState(buf []byte, sep string) *fastJSONState {
+	s := &fastJSONState{h: h, buf: buf, sep: sep, ok: true}
+	if h.replaceAttr != nil {
+		s.groups = slices.Clone(h.groups[:h.nOpenGroups])
+	}
+	return s
+}
+
+// fastJSONBufPool holds the buffers records are encoded into.
+var fastJSONBufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 1024)
+		return &buf
+	},
+}
+
+// fastJSONState holds the state of encoding a record or the attributes of
+// WithAttrs.
+type fastJSONState struct {
+	h      *fastJSONHandler
+	buf    []byte
+	sep    string   // separator to write before the next key
+	groups []string // groups passed to ReplaceAttr
+	ok     bool     // false once a value the fast path does not support was met
+}
+
+// openGroups opens the groups of WithGroup not yet opened in the preformatted attributes.
+func (s *fastJSONState) openGroups() {
+	for _, name := range s.h.groups[s.h.nOpenGroups:] {
+		s.openGroup(name)
+	}
+}
+
+// openGroup starts a group with the given name.
+func (s *fastJSONState) openGroup(name string) {
+	s.appendKey(name)
+	s.buf = append(s.buf, '{')
+	s.sep = ""
+	if s.h.replaceAttr != nil {
+		s.groups = append(s.groups, name)
+	}
+}
+
+// closeGroup ends the innermost group.
+func (s *fastJSONState) closeGroup() {
+	s.buf = append(s.buf, '}')
+	s.sep = ","
+	if s.h.replaceAttr != nil {
+		s.groups = s.groups[:len(s.groups)-1]
+	}
+}
+
+// appendAttr appends the key and value of a, applying ReplaceAttr, and
+// reports whether something was appended.
+func (s *fastJSONState) appendAttr(a slog.Attr) bool {
+	a.Value = a.Value.Resolve()
+	if rep := s.h.replaceAttr; rep != nil && a.Value.Kind() != slog.KindGroup {
+		a = rep(s.groups, a)
+		a.Value = a.Value.Resolve()
+	}
+	// Elide empty attributes.
+	if a.Key == "" && a.Value.Kind() == slog.KindAny && a.Value.Any() == nil {
+		return false
+	}
+	if v := a.Value; v.Kind() == slog.KindAny {
+		if src, ok := v.Any().(*slog.Source); ok {
+			if src == nil || *src == (slog.Source{}) {
+				return false
+			}
+			a.Value = sourceGroup(src)
+		}
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		s.appendKey(a.Key)
+		s.appendValue(a.Value)
+		return true
+	}
+	attrs := a.Value.Group()
+	if len(attrs) == 0 {
+		return false
+	}
+	pos, sep := len(s.buf), s.sep
+	if a.Key != "" {
+		s.openGroup(a.Key)
+	}
+	nonEmpty := false
+	for _, ga := range attrs {
+		if s.appendAttr(ga) {
+			nonEmpty = true
+		}
+	}
+	if !nonEmpty {
+		s.buf, s.sep = s.buf[:pos], sep
+		if a.Key != "" && s.h.replaceAttr != nil {
+			s.groups = s.groups[:len(s.groups)-1]
+		}
+		return false
+	}
+	if a.Key != "" {
+		s.closeGroup()
+	}
+	return true
+}
+
+// appendKey appends the separator and the key.
+func (s *fastJSONState) appendKey(key string) {
+	s.buf = append(s.buf, s.sep...)
+	s.appendString(key)
+	s.buf = append(s.buf, ':')
+	s.sep = ","
+}
+
+// appendString appends str as a JSON string.
+func (s *fastJSONState) appendString(str string) {
+	s.buf = append(s.buf, '"')
+	s.buf = appendEscapedJSON(s.buf, str)
+	s.buf = append(s.buf, '"')
+}
+
+// appendTime appends t in RFC 3339 format. Years outside of the format's
+// range are left to the standard handler.
+func (s *fastJSONState) appendTime(t time.Time) {
+	if y := t.Year(); y < 0 || y >= 10000 {
+		s.ok = false
+		return
+	}
+	s.buf = append(s.buf, '"')
+	s.buf = t.AppendFormat(s.buf, time.RFC3339Nano)
+	s.buf = append(s.buf, '"')
+}
+
+// appendValue appends v, or marks the state as not ok if v is not of one of
+// the kinds the fast path supports.
+func (s *fastJSONState) appendValue(v slog.Value) {
+	switch v.Kind() {
+	case slog.KindString:
+		s.appendString(v.String())
+	case slog.KindInt64:
+		s.buf = strconv.AppendInt(s.buf, v.Int64(), 10)
+	case slog.KindUint64:
+		s.buf = strconv.AppendUint(s.buf, v.Uint64(), 10)
+	case slog.KindFloat64:
+		f := v.Float64()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			s.ok = false
+			return
+		}
+		s.buf = appendJSONFloat(s.buf, f)
+	case slog.KindBool:
+		s.buf = strconv.AppendBool(s.buf, v.Bool())
+	case slog.KindDuration:
+		s.buf = strconv.AppendInt(s.buf, int64(v.Duration()), 10)
+	case slog.KindTime:
+		s.appendTime(v.Time())
+	default:
+		// The level is the only value of kind any the fast path encodes,
+		// as a ReplaceAttr function that keeps it passes it on.
+		if level, ok := v.Any().(slog.Level); ok {
+			s.appendString(level.String())
+			return
+		}
+		s.ok = false
+	}
+}
+
+// recordSource returns the source location of pc, or nil if pc is zero.
+func recordSource(pc uintptr) *slog.Source {
+	if pc == 0 {
+		return nil
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return &slog.Source{Function: frame.Function, File: frame.File, Line: frame.Line}
+}
+
+// sourceGroup returns src as a group of its non-zero fields, the form the
+// JSON handler logs it in.
+func sourceGroup(src *slog.Source) slog.Value {
+	var attrs []slog.Attr
+	if src.Function != "" {
+		attrs = append(attrs, slog.String("function", src.Function))
+	}
+	if src.File != "" {
+		attrs = append(attrs, slog.String("file", src.File))
+	}
+	if src.Line != 0 {
+		attrs = append(attrs, slog.Int("line", src.Line))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// appendJSONFloat appends the finite f formatted as encoding/json does.
+func appendJSONFloat(buf []byte, f float64) []byte {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if n := len(buf); n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+	return buf
+}
+
+// appendEscapedJSON appends the contents of a JSON string holding str,
+// escaped as slog.JSONHandler does: without HTML escaping, with invalid UTF-8
+// replaced by U+FFFD (see invalidUTF8JSON) and with U+2028 and U+2029 escaped.
+func appendEscapedJSON(buf []byte, str string) []byte {
+	const hex = "0123456789abcdef"
+	start := 0
+	for i := 0; i < len(str); {
+		if b := str[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' {
+				i++
+				continue
+			}
+			buf = append(buf, str[start:i]...)
+			switch b {
+			case '"', '\\':
+				buf = append(buf, '\\', b)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		c, size := utf8.DecodeRuneInString(str[i:])
+		if c == utf8.RuneError && size == 1 {
+			buf = append(buf, str[start:i]...)
+			buf = append(buf, invalidUTF8JSON()...)
+			i += size
+			start = i
+			continue
+		}
+		if c == '\u2028' || c == '\u2029' {
+			buf = append(buf, str[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hex[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	return append(buf, str[start:]...)
+}
+
+// invalidUTF8JSON returns the replacement slog.JSONHandler writes for a byte
+// of invalid UTF-8, which is either an escaped or a literal U+FFFD depending on
+// the Go version and the JSON experiment in use. It is taken from the output
+// of the JSON handler itself.
+var invalidUTF8JSON = sync.OnceValue(func() string {
+	var buf bytes.Buffer
+	onlyValue := func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key != "v" {
+			return slog.Attr{}
+		}
+		return a
+	}
+	slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: onlyValue})).Info("", "v", "\xff")
+	if replacement, ok := strings.CutPrefix(strings.TrimSpace(buf.String()), `{"v":"`); ok {
+		if replacement, ok = strings.CutSuffix(replacement, `"}`); ok {
+			return replacement
+		}
+	}
+	return `\ufffd`
+})
+
+// lockedWriter serializes the writes to w with mu.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+// Write implements io.Writer.
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
@@ -0,0 +1,150 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFastJSONHandlerMatchesJSONHandler(t *testing.T) {
+	var pcs [1]uintptr
+	runtime.Callers(1, pcs[:])
+	at := time.Date(2026, 10, 14, 9, 30, 0, 123456789, time.FixedZone("JST", 9*3600))
+
+	dropSecret := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == "secret" {
+			return slog.Attr{}
+		}
+		return a
+	}
+	options := map[string]*slog.HandlerOptions{
+		"no options":   {},
+		"source":       {AddSource: true},
+		"replace attr": {ReplaceAttr: dropSecret},
+		"repo chain":   {AddSource: true, ReplaceAttr: replaceAttr(&Config{HandlerType: "json", SourceFlatten: true})},
+	}
+
+	loggers := map[string]func(*slog.Logger) *slog.Logger{
+		"plain": func(l *slog.Logger) *slog.Logger { return l },
+		"with":  func(l *slog.Logger) *slog.Logger { return l.With("service", "api", "n", 1) },
+		"group": func(l *slog.Logger) *slog.Logger { return l.WithGroup("req") },
+		"group with": func(l *slog.Logger) *slog.Logger {
+			return l.With("a", 1).WithGroup("req").With("id", "r1").WithGroup("inner")
+		},
+		"empty with":   func(l *slog.Logger) *slog.Logger { return l.With(slog.Group("empty")).WithGroup("g").With("secret", 1) },
+		"slow with":    func(l *slog.Logger) *slog.Logger { return l.With("err", errors.New("bad")) },
+		"inline group": func(l *slog.Logger) *slog.Logger { return l.With(slog.Group("", "x", 1)) },
+	}
+
+	records := map[string][]any{
+		"no attrs": nil,
+		"common": {
+			"s", "line\nbreak \"quoted\" <tag> & \u2028 \x01 \xff é",
+			"i", -42, "u", uint64(math.MaxUint64), "b", true,
+			"d", 1500 * time.Millisecond, "t", at, "lv", slog.LevelWarn,
+		},
+		"floats": {"f1", 3.25, "f2", 1e21, "f3", 1e-7, "f4", 0.0, "f5", -123456789.0, "f6", 1e-6},
+		"groups": {
+			slog.Group("g", "a", 1, slog.Group("h", "b", 2)),
+			slog.Group("empty"),
+			slog.Group("", "inline", true),
+			"after", "y",
+		},
+		"valuer":      {"v", testLogValuer{"resolved"}},
+		"all dropped": {"secret", 1},
+		"fallback":    {"err", errors.New("boom"), "m", map[string]int{"k": 1}},
+		"nan":         {"f", math.NaN()},
+	}
+
+	for optName, opts := range options {
+		for loggerName, derive := range loggers {
+			for recordName, args := range records {
+				var std, fast bytes.Buffer
+				stdLogger := derive(slog.New(slog.NewJSONHandler(&std, opts)))
+				fastLogger := derive(slog.New(newFastJSONHandler(&fast, opts)))
+				for _, msg := range []string{"message", ""} {
+					r := slog.NewRecord(at, slog.LevelInfo, msg, pcs[0])
+					r.Add(args...)
+					_ = stdLogger.Handler().Handle(context.Background(), r.Clone())
+					_ = fastLogger.Handler().Handle(context.Background(), r)
+				}
+				if std.String() != fast.String() {
+					t.Errorf("%s/%s/%s: expected\n%s\ngot\n%s", optName, loggerName, recordName, std.String(), fast.String())
+				}
+			}
+		}
+	}
+}
+
+func TestFastJSONHandlerDroppedGroup(t *testing.T) {
+	var buf bytes.Buffer
+	dropSecret := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == "secret" || a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		return a
+	}
+	logger := slog.New(newFastJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: dropSecret}))
+	logger.Info("m", "a", 1, slog.Group("dropped", "secret", "x"), "b", 2)
+
+	expected := `{"level":"INFO","msg":"m","a":1,"b":2}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestReadConfigJSONFast(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerHandler: "json", EnvLoggerJSONFast: "true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.JSONFast {
+		t.Fatal("expected JSONFast to be enabled")
+	}
+
+	var buf bytes.Buffer
+	handler := createHandler(config, &buf)
+	if names := describeHandler(handler); names[len(names)-1] != "json(fast)" {
+		t.Errorf("expected a fast JSON handler, got %v", names)
+	}
+	slog.New(handler).Info("hello", "n", 1)
+	if !strings.Contains(buf.String(), `"msg":"hello","n":1}`) {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
+
+// benchmarkJSONHandler logs a record with attributes of the common kinds
+// through a logger with attributes, as a typical request log does.
+func benchmarkJSONHandler(b *testing.B, handler slog.Handler) {
+	logger := slog.New(handler).With("service", "api", "version", "1.2.3")
+	at := time.Now()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		logger.Info("request handled",
+			"method", "GET", "path", "/api/v1/orders", "status", 200,
+			"bytes", 5120, "cached", false, "ratio", 0.75,
+			"duration", 1234*time.Microsecond, "started", at)
+	}
+}
+
+func BenchmarkJSONHandler(b *testing.B) {
+	opts := &slog.HandlerOptions{ReplaceAttr: replaceAttr(&Config{HandlerType: "json"})}
+	b.Run("standard", func(b *testing.B) {
+		benchmarkJSONHandler(b, slog.NewJSONHandler(io.Discard, opts))
+	})
+	b.Run("fast", func(b *testing.B) {
+		benchmarkJSONHandler(b, newFastJSONHandler(io.Discard, opts))
+	})
+}
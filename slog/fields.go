@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// Fields converts a map of fields into attributes for the args of a logging
+// call, easing the migration from map-based loggers:
+//
+//	slog.Info("order placed", planks_slog.Fields(map[string]any{"id": id, "total": total})...)
+//
+// The attributes are sorted by key so that the output does not depend on the
+// map iteration order. Nested map[string]any values become groups, with their
+// attributes sorted in turn; other values are logged as slog.Any would.
+func Fields(m map[string]any) []any {
+	attrs := fieldAttrs(m)
+	args := make([]any, len(attrs))
+	for i, a := range attrs {
+		args[i] = a
+	}
+	return args
+}
+
+// fieldAttrs returns the attributes of m, sorted by key.
+func fieldAttrs(m map[string]any) []slog.Attr {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		if nested, ok := m[key].(map[string]any); ok {
+			attrs = append(attrs, slog.Attr{Key: key, Value: slog.GroupValue(fieldAttrs(nested)...)})
+			continue
+		}
+		attrs = append(attrs, slog.Any(key, m[key]))
+	}
+	return attrs
+}
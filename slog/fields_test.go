@@ -0,0 +1,34 @@
+package slog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: removeTime}))
+
+	logger.Info("order placed", Fields(map[string]any{
+		"total": 9.5,
+		"id":    "A-1",
+		"user": map[string]any{
+			"name":  "alice",
+			"email": "alice@example.com",
+			"prefs": map[string]any{"lang": "en"},
+		},
+		"count": 3,
+	})...)
+
+	expected := "level=INFO msg=\"order placed\" count=3 id=A-1 total=9.5 user.email=alice@example.com user.name=alice user.prefs.lang=en\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestFieldsEmpty(t *testing.T) {
+	if args := Fields(nil); len(args) != 0 {
+		t.Errorf("expected no attributes for a nil map, got %v", args)
+	}
+}
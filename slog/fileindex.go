@@ -0,0 +1,82 @@
+package slog
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileIndexSuffix is appended to the log file path to name its index file.
+const fileIndexSuffix = ".idx"
+
+// fileIndexInterval is the granularity of the index entries.
+const fileIndexInterval = time.Minute
+
+// fileIndexWriter is a writer wrapper for log files that keeps a sidecar
+// index of byte offsets for LOGGER_WRITER_FILE_INDEX, so that tools can seek
+// to a point in time in large log files.
+//
+// The first write of every minute adds a line "<minute> <offset>" to the
+// index, where minute is the wall-clock time of the write truncated to the
+// minute in RFC 3339 format and offset is the position in the log file at
+// which the write starts. The index is buffered and flushed when it is closed.
+type fileIndexWriter struct {
+	mu     sync.Mutex
+	file   *os.File
+	index  *os.File
+	buf    *bufio.Writer
+	offset int64
+	last   time.Time
+	now    func() time.Time
+}
+
+// newFileIndexWriter creates a new writer that writes to file and indexes it
+// in the file's index file, which is opened with the given flags and permissions.
+// Offsets start at the current size of file.
+func newFileIndexWriter(file *os.File, flag int, perm os.FileMode) (*fileIndexWriter, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	index, err := os.OpenFile(file.Name()+fileIndexSuffix, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &fileIndexWriter{
+		file:   file,
+		index:  index,
+		buf:    bufio.NewWriter(index),
+		offset: info.Size(),
+		now:    time.Now,
+	}, nil
+}
+
+// Write implements io.Writer.
+func (w *fileIndexWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if t := w.now().UTC().Truncate(fileIndexInterval); !t.Equal(w.last) {
+		// Index write errors surface when the index is closed, so
+		// that they do not fail the log write.
+		fmt.Fprintf(w.buf, "%s %d\n", t.Format(time.RFC3339), w.offset)
+		w.last = t
+	}
+	n, err := w.file.Write(p)
+	w.offset += int64(n)
+	return n, err
+}
+
+// Close flushes and closes the index file. It does not close the log file.
+func (w *fileIndexWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := w.buf.Flush()
+	if cerr := w.index.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,111 @@
+package slog
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileIndexWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old record\n"), 0o644); err != nil {
+		t.Fatalf("failed to write log file: %v", err)
+	}
+	config := &Config{WriterFilePerm: DefaultFilePerm}
+	f, err := os.OpenFile(path, fileOpenFlag(config), config.WriterFilePerm)
+	if err != nil {
+		t.Fatalf("failed to open log file: %v", err)
+	}
+	defer f.Close()
+
+	w, err := newFileIndexWriter(f, fileOpenFlag(config), config.WriterFilePerm)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Date(2026, 10, 14, 12, 0, 30, 0, time.UTC)
+	now := start
+	w.now = func() time.Time { return now }
+
+	for i, offset := range []time.Duration{0, 10 * time.Second, 40 * time.Second, 3 * time.Minute} {
+		now = start.Add(offset)
+		if _, err := w.Write([]byte("record " + strconv.Itoa(i) + "\n")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	log, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	index, err := os.ReadFile(path + fileIndexSuffix)
+	if err != nil {
+		t.Fatalf("failed to read index file: %v", err)
+	}
+
+	expected := map[string]string{
+		"2026-10-14T12:00:00Z": "record 0",
+		"2026-10-14T12:01:00Z": "record 2",
+		"2026-10-14T12:03:00Z": "record 3",
+	}
+	var minutes []string
+	scanner := bufio.NewScanner(strings.NewReader(string(index)))
+	for scanner.Scan() {
+		minute, offsetStr, _ := strings.Cut(scanner.Text(), " ")
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset > len(log) {
+			t.Fatalf("invalid index entry %q", scanner.Text())
+		}
+		line, _, _ := strings.Cut(string(log[offset:]), "\n")
+		if line != expected[minute] {
+			t.Errorf("expected entry for %s to point at %q, got %q", minute, expected[minute], line)
+		}
+		minutes = append(minutes, minute)
+	}
+	if len(minutes) != len(expected) {
+		t.Errorf("expected %d index entries, got %q", len(expected), index)
+	}
+}
+
+func TestBuildWriterFileIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	config := &Config{WriterType: "file", WriterFilePath: path, WriterFilePerm: DefaultFilePerm, WriterFileIndex: true}
+	w, err := buildWriter(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := w.Write([]byte("record\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	index, err := os.ReadFile(path + fileIndexSuffix)
+	if err != nil {
+		t.Fatalf("failed to read index file: %v", err)
+	}
+	if !strings.HasSuffix(string(index), " 0\n") || strings.Count(string(index), "\n") != 1 {
+		t.Errorf("unexpected index %q", index)
+	}
+}
+
+func TestReadConfigWriterFileIndex(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{
+		EnvLoggerWriter:          "file",
+		EnvLoggerWriterFilePath:  "/tmp/app.log",
+		EnvLoggerWriterFileIndex: "true",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.WriterFileIndex {
+		t.Error("expected WriterFileIndex to be enabled")
+	}
+}
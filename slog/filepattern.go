@@ -0,0 +1,49 @@
+package slog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// expandFilePath replaces the date/time placeholders in the file path with
+// the corresponding values of t:
+//
+//	%Y  four-digit year
+//	%m  two-digit month (01-12)
+//	%d  two-digit day of month (01-31)
+//	%H  two-digit hour (00-23)
+//	%%  a literal %
+//
+// Any other %, including a trailing one, is kept as it is, so that paths
+// containing a literal % stay valid.
+func expandFilePath(pattern string, t time.Time) string {
+	if !strings.Contains(pattern, "%") {
+		return pattern
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' || i+1 >= len(pattern) {
+			b.WriteByte(pattern[i])
+			continue
+		}
+		i++
+		switch pattern[i] {
+		case 'Y':
+			fmt.Fprintf(&b, "%04d", t.Year())
+		case 'm':
+			fmt.Fprintf(&b, "%02d", int(t.Month()))
+		case 'd':
+			fmt.Fprintf(&b, "%02d", t.Day())
+		case 'H':
+			fmt.Fprintf(&b, "%02d", t.Hour())
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(pattern[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package slog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandFilePath(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 7, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{pattern: "/var/log/app.log", expected: "/var/log/app.log"},
+		{pattern: "/var/log/app-%Y%m%d.log", expected: "/var/log/app-20240305.log"},
+		{pattern: "/var/log/%Y/%m/app-%d-%H.log", expected: "/var/log/2024/03/app-05-07.log"},
+		{pattern: "/var/log/100%%-%Y.log", expected: "/var/log/100%-2024.log"},
+		// Other % characters are kept, as in paths used before placeholders
+		{pattern: "/var/log/app%20name-%M.log", expected: "/var/log/app%20name-%M.log"},
+		{pattern: "/var/log/app-%", expected: "/var/log/app-%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := expandFilePath(tt.pattern, now); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package slog
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// loggerFlag describes a command-line flag that mirrors a logger environment variable.
+type loggerFlag struct {
+	name   string
+	envVar string
+	usage  string
+	isBool bool
+}
+
+// loggerFlags lists the flags registered by RegisterFlags.
+var loggerFlags = []loggerFlag{
+	{name: "log.level", envVar: EnvLoggerLevel, usage: "log level (debug, info, warn, error)"},
+	{name: "log.add-source", envVar: EnvLoggerAddSource, usage: "include source code position in logs", isBool: true},
+	{name: "log.handler", envVar: EnvLoggerHandler, usage: "log output format (json, text, bare, gelf, discard)"},
+	{name: "log.writer", envVar: EnvLoggerWriter, usage: "log destination (stdout, stderr, file, unixsocket)"},
+	{name: "log.file.path", envVar: EnvLoggerWriterFilePath, usage: "log file path when -log.writer=file"},
+	{name: "log.file.no-append", envVar: EnvLoggerWriterNoAppend, usage: "truncate the log file instead of appending", isBool: true},
+	{name: "log.file.perm", envVar: EnvLoggerWriterFilePerm, usage: "log file permissions in octal (e.g. 0644)"},
+}
+
+var (
+	flagSetMu sync.Mutex
+	flagSet   *flag.FlagSet
+)
+
+// RegisterFlags registers the logger flags (-log.level, -log.handler, -log.writer, ...)
+// on the given flag set. Call BuildFromFlags after the flag set has been parsed
+// to build a logger from them.
+func RegisterFlags(fs *flag.FlagSet) {
+	for _, f := range loggerFlags {
+		if f.isBool {
+			fs.Bool(f.name, false, f.usage)
+		} else {
+			fs.String(f.name, "", f.usage)
+		}
+	}
+
+	flagSetMu.Lock()
+	defer flagSetMu.Unlock()
+	flagSet = fs
+}
+
+// BuildFromFlags creates a logger from the flags registered by RegisterFlags.
+// Flags that were explicitly set on the command line take precedence over the
+// corresponding environment variables; any other setting falls back to the
+// environment, including PLANKS_ENV_PREFIX handling.
+// It returns the same errors as Build, including ErrNoEnvVarSet when neither
+// flags nor environment variables configure the logger.
+func BuildFromFlags() (*slog.Logger, error) {
+	flagSetMu.Lock()
+	fs := flagSet
+	flagSetMu.Unlock()
+
+	config, err := readConfig(flagLookup(fs, os.Getenv(EnvPlanksEnvPrefix)))
+	if err != nil {
+		return nil, err
+	}
+	return buildLogger(config)
+}
+
+// flagLookup returns a lookup function that resolves an environment variable
+// name to the value of its flag if that flag was set, or to the environment
+// variable otherwise.
+func flagLookup(fs *flag.FlagSet, prefix string) func(key string) string {
+	set := make(map[string]bool)
+	if fs != nil {
+		fs.Visit(func(f *flag.Flag) {
+			set[f.Name] = true
+		})
+	}
+
+	return func(key string) string {
+		for _, f := range loggerFlags {
+			if f.envVar != key || !set[f.name] {
+				continue
+			}
+			value := fs.Lookup(f.name).Value.String()
+			if f.isBool && value == "false" {
+				// Boolean environment variables are enabled by any value,
+				// so an explicit false must map to unset.
+				return ""
+			}
+			return value
+		}
+		return getEnv(prefix, key)
+	}
+}
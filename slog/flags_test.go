@@ -0,0 +1,161 @@
+package slog
+
+import (
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestFlagLookup(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer func() { flagSet = nil }()
+
+	tests := []struct {
+		name          string
+		args          []string
+		envVars       map[string]string
+		expected      *Config
+		expectedError error
+	}{
+		{
+			name:     "No Flags Or Environment Variables",
+			args:     []string{},
+			expected: nil,
+		},
+		{
+			name: "Flags Only",
+			args: []string{"-log.level=debug", "-log.handler=json", "-log.writer=stdout", "-log.add-source"},
+			expected: &Config{
+				Level:          slog.LevelDebug,
+				AddSource:      true,
+				HandlerType:    "json",
+				WriterType:     "stdout",
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+		{
+			name: "Environment Fallback",
+			args: []string{"-log.handler=json"},
+			envVars: map[string]string{
+				EnvLoggerLevel: "warn",
+			},
+			expected: &Config{
+				Level:          slog.LevelWarn,
+				HandlerType:    "json",
+				WriterType:     DefaultWriterType,
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+		{
+			name: "Flags Override Environment",
+			args: []string{"-log.level=error", "-log.add-source=false"},
+			envVars: map[string]string{
+				EnvLoggerLevel:     "debug",
+				EnvLoggerAddSource: "true",
+			},
+			expected: &Config{
+				Level:          slog.LevelError,
+				AddSource:      false,
+				HandlerType:    DefaultHandlerType,
+				WriterType:     DefaultWriterType,
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+		{
+			name: "File Flags",
+			args: []string{"-log.writer=file", "-log.file.path=/tmp/test.log", "-log.file.no-append", "-log.file.perm=0600"},
+			expected: &Config{
+				HandlerType:        DefaultHandlerType,
+				WriterType:         "file",
+				WriterFilePath:     "/tmp/test.log",
+				WriterFileNoAppend: true,
+				WriterFilePerm:     0600,
+			},
+		},
+		{
+			name: "Environment Prefix Fallback",
+			args: []string{},
+			envVars: map[string]string{
+				EnvPlanksEnvPrefix:       "TEST",
+				"TEST_" + EnvLoggerLevel: "warn",
+			},
+			expected: &Config{
+				Level:          slog.LevelWarn,
+				HandlerType:    DefaultHandlerType,
+				WriterType:     DefaultWriterType,
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+		{
+			name:          "Invalid Handler Flag",
+			args:          []string{"-log.handler=invalid"},
+			expectedError: ErrInvalidHandlerType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnvVars()
+			for k, v := range tt.envVars {
+				t.Setenv(k, v)
+			}
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("failed to parse flags: %v", err)
+			}
+
+			config, err := readConfig(flagLookup(fs, os.Getenv(EnvPlanksEnvPrefix)))
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("expected error to be '%v' but got '%v'", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.expected == nil {
+				if config != nil {
+					t.Errorf("expected nil config but got %+v", config)
+				}
+				return
+			}
+			if config == nil {
+				t.Fatalf("expected config %+v but got nil", tt.expected)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("expected config %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+}
+
+func TestBuildFromFlags(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer func() { flagSet = nil }()
+	clearEnvVars()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterFlags(fs)
+	if err := fs.Parse([]string{"-log.handler=discard"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	logger, err := BuildFromFlags()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if logger == nil {
+		t.Errorf("expected non-nil logger")
+	}
+}
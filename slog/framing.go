@@ -0,0 +1,34 @@
+package slog
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// lengthFrameWriter prefixes each write, which slog handlers issue once per
+// record, with its length as a 4-byte big-endian integer. This lets consumers
+// read records from a stream without scanning for newlines.
+type lengthFrameWriter struct {
+	w io.Writer
+}
+
+// newLengthFrameWriter creates a writer that length-prefixes each record written to w.
+func newLengthFrameWriter(w io.Writer) io.Writer {
+	return &lengthFrameWriter{w: w}
+}
+
+// Write implements io.Writer.
+func (f *lengthFrameWriter) Write(p []byte) (int, error) {
+	frame := make([]byte, 4+len(p))
+	binary.BigEndian.PutUint32(frame, uint32(len(p)))
+	copy(frame[4:], p)
+
+	// The frame is written in a single call so that records from concurrent
+	// handlers sharing the writer cannot interleave.
+	n, err := f.w.Write(frame)
+	n = max(n-4, 0)
+	if err == nil && n < len(p) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
@@ -0,0 +1,77 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestLengthFrameWriter(t *testing.T) {
+	var buf bytes.Buffer
+	config := &Config{HandlerType: "json", Framing: "length"}
+	logger := slog.New(createHandler(config, wrapWriter(config, &buf)))
+
+	logger.Info("first")
+	logger.Info("second", "key", "value")
+
+	// Read the records back using only the length prefixes
+	var messages []string
+	for buf.Len() > 0 {
+		var header [4]byte
+		if _, err := io.ReadFull(&buf, header[:]); err != nil {
+			t.Fatalf("failed to read frame header: %v", err)
+		}
+		frame := make([]byte, binary.BigEndian.Uint32(header[:]))
+		if _, err := io.ReadFull(&buf, frame); err != nil {
+			t.Fatalf("failed to read frame body: %v", err)
+		}
+
+		var record struct {
+			Msg string `json:"msg"`
+		}
+		if err := json.Unmarshal(frame, &record); err != nil {
+			t.Fatalf("failed to parse frame %q: %v", frame, err)
+		}
+		messages = append(messages, record.Msg)
+	}
+
+	if len(messages) != 2 || messages[0] != "first" || messages[1] != "second" {
+		t.Errorf("expected frames for 'first' and 'second', got %v", messages)
+	}
+}
+
+func TestLengthFrameWriterBytes(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := newLengthFrameWriter(&buf).Write([]byte("hello\n"))
+	if err != nil || n != 6 {
+		t.Fatalf("expected 6 bytes written without error, got %d, %v", n, err)
+	}
+
+	expected := []byte{0, 0, 0, 6, 'h', 'e', 'l', 'l', 'o', '\n'}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("expected %v, got %v", expected, buf.Bytes())
+	}
+}
+
+func TestReadConfigFraming(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerFraming: "LENGTH"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Framing != "length" {
+		t.Errorf("expected framing 'length', got %q", config.Framing)
+	}
+
+	_, err = readConfigFromEnv(t, map[string]string{EnvLoggerFraming: "varint"})
+	if !errors.Is(err, ErrInvalidFraming) {
+		t.Errorf("expected ErrInvalidFraming, got %v", err)
+	}
+}
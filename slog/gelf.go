@@ -0,0 +1,119 @@
+package slog
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// GELFVersion is the version of the GELF format written by LOGGER_HANDLER=gelf.
+const GELFVersion = "1.1"
+
+// gelfEncoder is an Encoder that writes records as GELF messages for Graylog,
+// one JSON object per line.
+//
+// The message becomes short_message, the time a timestamp in seconds and the
+// level the syslog severity of gelfLevel. User attributes become additional
+// fields, prefixed with an underscore; attributes in groups are flattened to
+// their dotted path ("_req.id"), since GELF has no nested fields. Numbers stay
+// numbers and every other value is logged as a string. The reserved field
+// "_id" is logged as "__id".
+type gelfEncoder struct {
+	host      string
+	addSource bool
+}
+
+// newGELFEncoder creates a new GELF encoder for the given host. An empty host
+// stands for the hostname reported by the kernel.
+func newGELFEncoder(host string, addSource bool) *gelfEncoder {
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	return &gelfEncoder{host: host, addSource: addSource}
+}
+
+// Encode implements Encoder.
+func (e *gelfEncoder) Encode(w io.Writer, r slog.Record) error {
+	buf := []byte(`{"version":"` + GELFVersion + `","host":`)
+	buf = appendJSONString(buf, e.host)
+	buf = append(buf, `,"short_message":`...)
+	buf = appendJSONString(buf, r.Message)
+	if !r.Time.IsZero() {
+		buf = append(buf, `,"timestamp":`...)
+		buf = strconv.AppendFloat(buf, float64(r.Time.UnixMicro())/1e6, 'f', -1, 64)
+	}
+	buf = append(buf, `,"level":`...)
+	buf = strconv.AppendInt(buf, int64(gelfLevel(r.Level)), 10)
+	if e.addSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		buf = append(buf, `,"_file":`...)
+		buf = appendJSONString(buf, frame.File)
+		buf = append(buf, `,"_line":`...)
+		buf = strconv.AppendInt(buf, int64(frame.Line), 10)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		buf = appendGELFField(buf, "", a)
+		return true
+	})
+	buf = append(buf, "}\n"...)
+	_, err := w.Write(buf)
+	return err
+}
+
+// appendGELFField appends a as additional fields, flattening groups, with
+// prefix holding the dotted path of the enclosing groups.
+func appendGELFField(buf []byte, prefix string, a slog.Attr) []byte {
+	key := prefix + a.Key
+	if a.Value.Kind() == slog.KindGroup {
+		for _, ga := range a.Value.Group() {
+			buf = appendGELFField(buf, key+".", ga)
+		}
+		return buf
+	}
+	if key == "id" {
+		key = "_id"
+	}
+	buf = append(buf, ',')
+	buf = appendJSONString(buf, "_"+key)
+	buf = append(buf, ':')
+	switch v := a.Value; v.Kind() {
+	case slog.KindInt64:
+		return strconv.AppendInt(buf, v.Int64(), 10)
+	case slog.KindUint64:
+		return strconv.AppendUint(buf, v.Uint64(), 10)
+	case slog.KindFloat64:
+		if f := v.Float64(); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return strconv.AppendFloat(buf, f, 'g', -1, 64)
+		}
+	case slog.KindTime:
+		return appendJSONString(buf, v.Time().Format(time.RFC3339Nano))
+	}
+	return appendJSONString(buf, a.Value.String())
+}
+
+// gelfLevel returns the syslog severity of level: 7 (debug) below info,
+// 6 (informational) below warn, 4 (warning) below error and 3 (error) from
+// error up.
+func gelfLevel(level slog.Level) int {
+	switch {
+	case level < slog.LevelInfo:
+		return 7
+	case level < slog.LevelWarn:
+		return 6
+	case level < slog.LevelError:
+		return 4
+	default:
+		return 3
+	}
+}
+
+// appendJSONString appends s to buf as a JSON string.
+func appendJSONString(buf []byte, s string) []byte {
+	b, _ := json.Marshal(s)
+	return append(buf, b...)
+}
@@ -0,0 +1,108 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGELFHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "gelf", GELFHost: "web-1"}, &buf))
+
+	logger.With("service", "api").WithGroup("req").With("id", 7).Warn("slow request",
+		"elapsed", 1500*time.Millisecond, "ratio", 0.5, "ok", true, "nan", math.NaN(), slog.Group("db", "rows", 3))
+	logger.Info("reserved", "id", "abc")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 GELF messages, got %q", buf.String())
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", lines[0], err)
+	}
+	ts, ok := got["timestamp"].(float64)
+	if !ok || math.Abs(ts-float64(time.Now().UnixMicro())/1e6) > 60 {
+		t.Errorf("expected a timestamp in seconds, got %v", got["timestamp"])
+	}
+	delete(got, "timestamp")
+	expected := map[string]any{
+		"version":       "1.1",
+		"host":          "web-1",
+		"short_message": "slow request",
+		"level":         float64(4),
+		"_service":      "api",
+		"_req.id":       float64(7),
+		"_req.elapsed":  "1.5s",
+		"_req.ratio":    0.5,
+		"_req.ok":       "true",
+		"_req.nan":      "NaN",
+		"_req.db.rows":  float64(3),
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected GELF message:\n got  %v\n want %v", got, expected)
+	}
+	if prefix := `{"version":"1.1","host":"web-1","short_message":"slow request","timestamp":`; !strings.HasPrefix(lines[0], prefix) {
+		t.Errorf("expected the GELF fields first, got %s", lines[0])
+	}
+
+	// The reserved _id field is not written
+	if !strings.Contains(lines[1], `"__id":"abc"`) || strings.Contains(lines[1], `"_id"`) {
+		t.Errorf("expected the id attribute as __id, got %s", lines[1])
+	}
+}
+
+func TestGELFLevel(t *testing.T) {
+	tests := []struct {
+		level    slog.Level
+		severity int
+	}{
+		{slog.LevelDebug - 4, 7},
+		{slog.LevelDebug, 7},
+		{slog.LevelInfo, 6},
+		{slog.LevelInfo + 2, 6},
+		{slog.LevelWarn, 4},
+		{slog.LevelError, 3},
+		{slog.LevelError + 4, 3},
+	}
+	for _, tt := range tests {
+		if got := gelfLevel(tt.level); got != tt.severity {
+			t.Errorf("%v: expected severity %d, got %d", tt.level, tt.severity, got)
+		}
+	}
+}
+
+func TestGELFHandlerSource(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "gelf", AddSource: true}, &buf))
+	logger.Info("test")
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", buf.String(), err)
+	}
+	if file, _ := got["_file"].(string); !strings.HasSuffix(file, "gelf_test.go") || got["_line"] == nil {
+		t.Errorf("expected the source as _file and _line, got %v", got)
+	}
+	if hostname, _ := os.Hostname(); got["host"] != hostname {
+		t.Errorf("expected the hostname %q as host, got %v", hostname, got["host"])
+	}
+}
+
+func TestReadConfigGELF(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerHandler: "GELF", EnvLoggerGELFHost: "web-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.HandlerType != "gelf" || config.GELFHost != "web-1" {
+		t.Errorf("unexpected GELF settings: %q %q", config.HandlerType, config.GELFHost)
+	}
+}
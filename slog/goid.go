@@ -0,0 +1,77 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"runtime"
+	"strconv"
+)
+
+// goidHandler is a wrapper handler that adds the id of the logging goroutine
+// to each record as a "goid" attribute.
+//
+// Go deliberately does not expose goroutine ids, so the id is extracted on a
+// best-effort basis by parsing the output of runtime.Stack. This costs a stack
+// capture per record and is meant for debugging concurrency issues only.
+type goidHandler struct {
+	next slog.Handler
+}
+
+// newGoIDHandler creates a new handler that adds the goroutine id to each record.
+func newGoIDHandler(next slog.Handler) slog.Handler {
+	return &goidHandler{next: next}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *goidHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *goidHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id, ok := goroutineID(); ok {
+		r = r.Clone()
+		r.AddAttrs(slog.Uint64("goid", id))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *goidHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &goidHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *goidHandler) WithGroup(name string) slog.Handler {
+	return &goidHandler{next: h.next.WithGroup(name)}
+}
+
+// describe implements describer.
+func (h *goidHandler) describe() string {
+	return "goid"
+}
+
+// unwrap implements describer.
+func (h *goidHandler) unwrap() slog.Handler {
+	return h.next
+}
+
+// goroutineID returns the id of the calling goroutine, parsed from the
+// "goroutine <id> [<state>]:" header of its stack trace.
+func goroutineID() (uint64, bool) {
+	var buf [64]byte
+	stack := buf[:runtime.Stack(buf[:], false)]
+	stack, ok := bytes.CutPrefix(stack, []byte("goroutine "))
+	if !ok {
+		return 0, false
+	}
+	if i := bytes.IndexByte(stack, ' '); i >= 0 {
+		stack = stack[:i]
+	}
+	id, err := strconv.ParseUint(string(stack), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
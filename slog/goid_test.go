@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGoIDHandler(t *testing.T) {
+	buffer := newTestBufferHandler()
+	logger := slog.New(newGoIDHandler(buffer))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mu.Lock()
+			defer mu.Unlock()
+			logger.Info("from goroutine")
+		}()
+	}
+	wg.Wait()
+
+	if len(buffer.logs) != 2 {
+		t.Fatalf("expected 2 logs, got %d", len(buffer.logs))
+	}
+	var ids []string
+	for _, log := range buffer.logs {
+		_, id, ok := strings.Cut(log, "goid=")
+		if !ok {
+			t.Fatalf("expected goid attribute in %q", log)
+		}
+		ids = append(ids, id)
+	}
+	if ids[0] == ids[1] {
+		t.Errorf("expected different goroutine ids, got %s twice", ids[0])
+	}
+}
+
+func TestGoroutineID(t *testing.T) {
+	id, ok := goroutineID()
+	if !ok || id == 0 {
+		t.Errorf("expected a goroutine id, got %d (ok=%v)", id, ok)
+	}
+
+	again, _ := goroutineID()
+	if again != id {
+		t.Errorf("expected stable id within a goroutine, got %d and %d", id, again)
+	}
+}
@@ -0,0 +1,62 @@
+package slog
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+)
+
+// DefaultHashLength is the default number of hex digits kept from the hash of
+// a pseudonymized attribute value.
+const DefaultHashLength = 16
+
+// maxHashLength is the number of hex digits of a full hash.
+const maxHashLength = 2 * sha256.Size
+
+// replaceHashKeys returns a ReplaceAttr function that replaces the string
+// values of the attributes listed in the config's hash keys with a salted
+// hash, or nil if no hash keys are configured.
+//
+// The hash is the HMAC-SHA256 of the value keyed with the salt, hex-encoded and
+// truncated to HashLength digits, so equal values map to equal hashes and
+// records can still be correlated. Keys are matched case-insensitively, by key
+// or, for attributes inside groups, by dotted path ("user.email"). Values of
+// other kinds and the built-in attributes are left as they are.
+func replaceHashKeys(config *Config) func([]string, slog.Attr) slog.Attr {
+	if len(config.HashKeys) == 0 {
+		return nil
+	}
+
+	keys := make(map[string]bool, len(config.HashKeys))
+	for _, key := range config.HashKeys {
+		keys[strings.ToLower(key)] = true
+	}
+	salt := []byte(config.HashSalt)
+	length := config.HashLength
+	if length <= 0 {
+		length = DefaultHashLength
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() != slog.KindString || (len(groups) == 0 && isBuiltinKey(a.Key)) {
+			return a
+		}
+		key := strings.ToLower(a.Key)
+		if !keys[key] && (len(groups) == 0 || !keys[strings.ToLower(strings.Join(groups, "."))+"."+key]) {
+			return a
+		}
+		a.Value = slog.StringValue(hashValue(salt, a.Value.String(), length))
+		return a
+	}
+}
+
+// hashValue returns the first length hex digits of the HMAC-SHA256 of value
+// keyed with salt.
+func hashValue(salt []byte, value string, length int) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(value))
+	sum := hex.EncodeToString(mac.Sum(nil))
+	return sum[:min(length, len(sum))]
+}
@@ -0,0 +1,83 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestReplaceHashKeys(t *testing.T) {
+	config := &Config{HandlerType: "bare", HashKeys: []string{"User_Email", "req.ip"}, HashSalt: "pepper", HashLength: DefaultHashLength}
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(config, &buf))
+
+	logger.Info("login", "user_email", "alice@example.com", "USER_EMAIL", "alice@example.com", "count", 3,
+		slog.Group("req", "ip", "192.0.2.1"), "ip", "192.0.2.1")
+
+	hash := hashValue([]byte("pepper"), "alice@example.com", DefaultHashLength)
+	ipHash := hashValue([]byte("pepper"), "192.0.2.1", DefaultHashLength)
+	if len(hash) != DefaultHashLength {
+		t.Fatalf("expected a %d digit hash, got %q", DefaultHashLength, hash)
+	}
+	expected := "msg=login user_email=" + hash + " USER_EMAIL=" + hash + " count=3 req.ip=" + ipHash + " ip=192.0.2.1\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+	if strings.Contains(buf.String(), "alice") {
+		t.Errorf("expected the value to be hashed, got %q", buf.String())
+	}
+}
+
+func TestHashValue(t *testing.T) {
+	a := hashValue([]byte("salt"), "alice@example.com", 64)
+	if a != hashValue([]byte("salt"), "alice@example.com", 64) {
+		t.Errorf("expected stable hashes")
+	}
+	if a == hashValue([]byte("other"), "alice@example.com", 64) {
+		t.Errorf("expected the salt to change the hash")
+	}
+	if a == hashValue([]byte("salt"), "bob@example.com", 64) {
+		t.Errorf("expected different values to hash differently")
+	}
+	if short := hashValue([]byte("salt"), "alice@example.com", 8); short != a[:8] {
+		t.Errorf("expected the truncated hash %q, got %q", a[:8], short)
+	}
+}
+
+func TestReadConfigHashKeys(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{
+		EnvLoggerHashKeys:   "user_email, user.phone",
+		EnvLoggerHashSalt:   "pepper",
+		EnvLoggerHashLength: "12",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(config.HashKeys, ",") != "user_email,user.phone" || config.HashSalt != "pepper" || config.HashLength != 12 {
+		t.Errorf("unexpected hash settings: %v %q %d", config.HashKeys, config.HashSalt, config.HashLength)
+	}
+
+	for _, length := range []string{"0", "65", "long"} {
+		_, err := readConfigFromEnv(t, map[string]string{EnvLoggerHashKeys: "user_email", EnvLoggerHashSalt: "pepper", EnvLoggerHashLength: length})
+		if !errors.Is(err, ErrInvalidHashLength) {
+			t.Errorf("%q: expected ErrInvalidHashLength, got %v", length, err)
+		}
+	}
+}
+
+func TestReadConfigHashWithoutSalt(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerHashKeys: "user_email"})
+	if err != nil {
+		t.Fatalf("expected a warning instead of an error, got %v", err)
+	}
+	if len(config.warnings) != 1 {
+		t.Errorf("expected one warning, got %v", config.warnings)
+	}
+
+	_, err = readConfigFromEnv(t, map[string]string{EnvLoggerHashKeys: "user_email", EnvPlanksStrict: "soft"})
+	if !errors.Is(err, ErrMissingHashSalt) || ErrorSeverity(err) != SeveritySoft {
+		t.Errorf("expected a soft ErrMissingHashSalt in strict mode, got %v", err)
+	}
+}
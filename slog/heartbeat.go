@@ -0,0 +1,48 @@
+package slog
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// processStart is the reference point for the uptime reported by heartbeats
+// and LOGGER_ADD_UPTIME.
+var processStart = time.Now()
+
+var (
+	heartbeatMu      sync.Mutex
+	currentHeartbeat *periodicTask
+)
+
+// setHeartbeat starts a background goroutine that logs an info "heartbeat"
+// record with the process uptime through the default logger every interval.
+// A heartbeat started earlier is stopped first, so that re-initializing the
+// package does not multiply heartbeats; an interval of 0 only stops it.
+// The heartbeat is stopped by Close.
+func setHeartbeat(interval time.Duration) {
+	heartbeatMu.Lock()
+	defer heartbeatMu.Unlock()
+	if currentHeartbeat != nil {
+		currentHeartbeat.stop()
+		currentHeartbeat = nil
+	}
+	if interval <= 0 {
+		return
+	}
+
+	hb := startPeriodic(interval, false, func() {
+		slog.Info("heartbeat", "uptime", time.Since(processStart).Round(time.Millisecond))
+	})
+	currentHeartbeat = hb
+
+	registerCloser(closeStopTasks, func() error {
+		heartbeatMu.Lock()
+		defer heartbeatMu.Unlock()
+		hb.stop()
+		if currentHeartbeat == hb {
+			currentHeartbeat = nil
+		}
+		return nil
+	})
+}
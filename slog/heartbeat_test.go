@@ -0,0 +1,96 @@
+package slog
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// recordCount returns the number of records handled by h.
+func (h *recordingHandler) recordCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.records)
+}
+
+func TestHeartbeat(t *testing.T) {
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	h := &recordingHandler{}
+	slog.SetDefault(slog.New(h))
+
+	setHeartbeat(5 * time.Millisecond)
+	for deadline := time.Now().Add(time.Second); h.recordCount() < 2; {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected heartbeats, got %d records", h.recordCount())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	count := h.recordCount()
+	time.Sleep(20 * time.Millisecond)
+	if h.recordCount() != count {
+		t.Errorf("expected no heartbeats after Close, got %d more", h.recordCount()-count)
+	}
+
+	h.mu.Lock()
+	r := h.records[0]
+	h.mu.Unlock()
+	if r.Message != "heartbeat" || r.Level != slog.LevelInfo {
+		t.Errorf("unexpected record: %v %q", r.Level, r.Message)
+	}
+	var uptime slog.Value
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == "uptime" {
+			uptime = a.Value
+		}
+		return true
+	})
+	if uptime.Kind() != slog.KindDuration || uptime.Duration() <= 0 {
+		t.Errorf("expected a positive uptime, got %v", uptime)
+	}
+}
+
+func TestHeartbeatRestart(t *testing.T) {
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	defer Close()
+
+	h := &recordingHandler{}
+	slog.SetDefault(slog.New(h))
+
+	setHeartbeat(5 * time.Millisecond)
+	first := currentHeartbeat
+	setHeartbeat(0)
+
+	select {
+	case <-first.stopped:
+	default:
+		t.Error("expected the previous heartbeat to be stopped")
+	}
+	if currentHeartbeat != nil {
+		t.Error("expected no heartbeat for a zero interval")
+	}
+}
+
+func TestReadConfigHeartbeatInterval(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerHeartbeatInterval: "30s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.HeartbeatInterval != 30*time.Second {
+		t.Errorf("expected 30s, got %v", config.HeartbeatInterval)
+	}
+
+	for _, interval := range []string{"soon", "0s", "-1s"} {
+		_, err := readConfigFromEnv(t, map[string]string{EnvLoggerHeartbeatInterval: interval})
+		if !errors.Is(err, ErrInvalidHeartbeatInterval) {
+			t.Errorf("%q: expected ErrInvalidHeartbeatInterval, got %v", interval, err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// htmlEscapeWriter is a writer for JSON handlers that escapes the HTML-unsafe
+// characters <, > and & as \u003c, \u003e and \u0026, as encoding/json does
+// with SetEscapeHTML(true), so that records can be embedded in HTML.
+//
+// The characters can only occur inside JSON strings, where the escapes
+// decode to the same characters, so escaping them in the encoded record
+// leaves its meaning unchanged.
+type htmlEscapeWriter struct {
+	w io.Writer
+}
+
+// newHTMLEscapeWriter creates a new writer that HTML-escapes the JSON written to w.
+func newHTMLEscapeWriter(w io.Writer) io.Writer {
+	return &htmlEscapeWriter{w: w}
+}
+
+// Write implements io.Writer.
+// It writes each record with a single call to the underlying writer.
+func (w *htmlEscapeWriter) Write(p []byte) (int, error) {
+	var buf bytes.Buffer
+	json.HTMLEscape(&buf, p)
+	if _, err := w.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
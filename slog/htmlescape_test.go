@@ -0,0 +1,51 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestJSONHTMLEscape(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "json", JSONHTMLEscape: true}, &buf))
+	logger.Info("<b>bold</b>", "html", `<a href="/?x=1&y=2">link</a>`, slog.Group("<g>", "k&v", "a>b"))
+
+	output := buf.String()
+	if strings.ContainsAny(output, "<>&") {
+		t.Errorf("expected HTML-unsafe characters to be escaped, got %q", output)
+	}
+	if !strings.Contains(output, `"html":"\u003ca href=\"/?x=1\u0026y=2\"\u003elink\u003c/a\u003e"`) {
+		t.Errorf("expected the escaped value, got %q", output)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to parse output %q: %v", output, err)
+	}
+	if record["msg"] != "<b>bold</b>" || record["<g>"].(map[string]any)["k&v"] != "a>b" {
+		t.Errorf("expected the escapes to decode to the original values, got %v", record)
+	}
+}
+
+func TestJSONHTMLEscapeDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "json"}, &buf))
+	logger.Info("test", "html", "<b>&</b>")
+
+	if !strings.Contains(buf.String(), `"html":"<b>&</b>"`) {
+		t.Errorf("expected no HTML escaping by default, got %q", buf.String())
+	}
+}
+
+func TestReadConfigJSONHTMLEscape(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerJSONHTMLEscape: "true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.JSONHTMLEscape {
+		t.Errorf("expected HTML escaping to be enabled")
+	}
+}
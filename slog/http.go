@@ -0,0 +1,122 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"slices"
+)
+
+// HTTPMiddlewareOption configures the middleware created by NewHTTPMiddleware.
+type HTTPMiddlewareOption func(*httpMiddleware)
+
+// httpMiddleware holds the settings of the middleware created by NewHTTPMiddleware.
+type httpMiddleware struct {
+	panicResponse func(w http.ResponseWriter, r *http.Request, v any)
+	levelHeader   string
+	levels        []slog.Level
+}
+
+// WithPanicResponse sets the function that writes the response after a
+// downstream handler panicked with value v. By default the middleware responds
+// with 500 Internal Server Error.
+func WithPanicResponse(fn func(w http.ResponseWriter, r *http.Request, v any)) HTTPMiddlewareOption {
+	return func(m *httpMiddleware) {
+		m.panicResponse = fn
+	}
+}
+
+// WithLevelHeader lets callers request the level of their request's logs with
+// the given header, for example "X-Log-Level: debug" for distributed
+// debugging. The header holds a level name as in LOGGER_LEVEL. Only the
+// allowed levels are honored, so that clients cannot flood the logs with
+// arbitrary verbosity; other values and requests without the header log at
+// the logger's own level. See WithLevel.
+func WithLevelHeader(header string, allowed ...slog.Level) HTTPMiddlewareOption {
+	return func(m *httpMiddleware) {
+		m.levelHeader = header
+		m.levels = allowed
+	}
+}
+
+// requestLevel returns the level requested by r with the level header, and
+// whether it is set and allowed.
+func (m *httpMiddleware) requestLevel(r *http.Request) (slog.Level, bool) {
+	if m.levelHeader == "" {
+		return 0, false
+	}
+	value := r.Header.Get(m.levelHeader)
+	if value == "" {
+		return 0, false
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return 0, false
+	}
+	return level, slices.Contains(m.levels, level)
+}
+
+// NewHTTPMiddleware returns HTTP middleware that stores a request-scoped
+// logger in each request's context. The logger is derived from the context
+// logger (or slog.Default()) and carries the request method and path, so
+// downstream handlers get them on every context-aware log.
+//
+// Panics in downstream handlers are recovered, logged at error level with the
+// stack trace through the request-scoped logger, and turned into an error
+// response. http.ErrAbortHandler is re-panicked so that net/http can abort the
+// response as usual.
+func NewHTTPMiddleware(opts ...HTTPMiddlewareOption) func(http.Handler) http.Handler {
+	m := &httpMiddleware{
+		panicResponse: defaultPanicResponse,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			logger := FromContext(ctx).With("method", r.Method, "path", r.URL.Path)
+			if level, ok := m.requestLevel(r); ok {
+				logger = slog.New(newLevelOverrideHandler(logger.Handler(), level))
+			}
+			ctx = WithContext(ctx, logger)
+			r = r.WithContext(ctx)
+
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				logger.ErrorContext(ctx, "panic recovered",
+					"panic", v,
+					"remote_addr", r.RemoteAddr,
+					"stack", string(debug.Stack()))
+				m.panicResponse(w, r, v)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// BaseContextFunc returns a function for http.Server.BaseContext that seeds
+// the base context of every connection with logger as the context logger.
+// Requests served by handlers that bypass the middleware then still get
+// logger from FromContext, and the middleware derives its request-scoped
+// logger from it.
+func BaseContextFunc(logger *slog.Logger) func(net.Listener) context.Context {
+	return func(net.Listener) context.Context {
+		return WithContext(context.Background(), logger)
+	}
+}
+
+// defaultPanicResponse responds with 500 Internal Server Error.
+func defaultPanicResponse(w http.ResponseWriter, _ *http.Request, _ any) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
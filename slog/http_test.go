@@ -0,0 +1,149 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	contextHandler := newAttrBufferHandler()
+	baseCtx := WithContext(context.Background(), slog.New(contextHandler))
+
+	handler := NewHTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).InfoContext(r.Context(), "handled")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil).WithContext(baseCtx)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", rec.Code)
+	}
+	if len(contextHandler.logs) != 1 {
+		t.Fatalf("expected 1 log through the request logger, got %v", contextHandler.logs)
+	}
+	if log := contextHandler.logs[0]; !strings.Contains(log, "method=GET") || !strings.Contains(log, "path=/items") {
+		t.Errorf("expected request attributes, got %q", log)
+	}
+}
+
+func TestHTTPMiddlewarePanic(t *testing.T) {
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	tests := []struct {
+		name         string
+		opts         []HTTPMiddlewareOption
+		expectedCode int
+	}{
+		{
+			name:         "Default Response",
+			expectedCode: http.StatusInternalServerError,
+		},
+		{
+			name: "Custom Response",
+			opts: []HTTPMiddlewareOption{
+				WithPanicResponse(func(w http.ResponseWriter, r *http.Request, v any) {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}),
+			},
+			expectedCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contextHandler := newAttrBufferHandler()
+			baseCtx := WithContext(context.Background(), slog.New(contextHandler))
+
+			req := httptest.NewRequest(http.MethodPost, "/crash", nil).WithContext(baseCtx)
+			rec := httptest.NewRecorder()
+			NewHTTPMiddleware(tt.opts...)(panicking).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedCode {
+				t.Errorf("expected status %d, got %d", tt.expectedCode, rec.Code)
+			}
+			if len(contextHandler.logs) != 1 {
+				t.Fatalf("expected 1 panic log, got %v", contextHandler.logs)
+			}
+			log := contextHandler.logs[0]
+			for _, want := range []string{"ERROR: panic recovered", "method=POST", "path=/crash", "panic=boom", "stack="} {
+				if !strings.Contains(log, want) {
+					t.Errorf("expected %q in panic log %q", want, log)
+				}
+			}
+		})
+	}
+}
+
+func TestBaseContextFunc(t *testing.T) {
+	contextHandler := newAttrBufferHandler()
+	base := slog.New(contextHandler)
+
+	var got *slog.Logger
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+		got.InfoContext(r.Context(), "handled")
+	}))
+	server.Config.BaseContext = BaseContextFunc(base)
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/items")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if got != base {
+		t.Errorf("expected the base logger from the request context, got %v", got)
+	}
+	if len(contextHandler.logs) != 1 {
+		t.Errorf("expected 1 log through the base logger, got %v", contextHandler.logs)
+	}
+}
+
+func TestHTTPMiddlewareLevelHeader(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: removeTime}))
+	baseCtx := WithContext(context.Background(), base)
+
+	handler := NewHTTPMiddleware(WithLevelHeader("X-Log-Level", slog.LevelDebug))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).DebugContext(r.Context(), "details")
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		logged bool
+	}{
+		{name: "Allowed Level", header: "debug", logged: true},
+		{name: "No Header"},
+		{name: "Disallowed Level", header: "DEBUG-4"},
+		{name: "Invalid Level", header: "verbose"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/items", nil).WithContext(baseCtx)
+			if tt.header != "" {
+				req.Header.Set("X-Log-Level", tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if expected := "level=DEBUG msg=details method=GET path=/items\n"; tt.logged && buf.String() != expected {
+				t.Errorf("expected %q, got %q", expected, buf.String())
+			}
+			if !tt.logged && buf.Len() != 0 {
+				t.Errorf("expected the logger's own level, got %q", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrJobPanic is returned by Job.Run when the job function panics.
+var ErrJobPanic = errors.New("job panicked")
+
+// Job carries a payload together with the context it originated from, so that
+// the context logger flows to the goroutine that processes the payload, for
+// example a worker reading jobs from a channel.
+type Job[T any] struct {
+	// Ctx is the context the job was submitted with.
+	Ctx context.Context
+	// Payload is the work item.
+	Payload T
+}
+
+// NewJob creates a job carrying the given context and payload.
+func NewJob[T any](ctx context.Context, payload T) Job[T] {
+	return Job[T]{Ctx: ctx, Payload: payload}
+}
+
+// Submit sends a job carrying ctx and payload on ch. It blocks until a worker
+// receives the job or ctx is done, in which case it returns ctx.Err().
+func Submit[T any](ctx context.Context, ch chan<- Job[T], payload T) error {
+	select {
+	case ch <- NewJob(ctx, payload):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run calls fn with the job's context and payload and returns its error.
+// If fn panics, the panic is recovered and logged at error level with its
+// stack trace through the job's context logger, and Run returns an error
+// wrapping ErrJobPanic.
+func (j Job[T]) Run(fn func(ctx context.Context, payload T) error) (err error) {
+	ctx := j.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	defer func() {
+		if v := recover(); v != nil {
+			FromContext(ctx).ErrorContext(ctx, "job panicked", "panic", v, "stack", string(debug.Stack()))
+			err = fmt.Errorf("%w: %v", ErrJobPanic, v)
+		}
+	}()
+
+	return fn(ctx, j.Payload)
+}
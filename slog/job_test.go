@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestJob(t *testing.T) {
+	contextHandler := newTestBufferHandler()
+	ctx := context.WithValue(context.Background(), ContextLoggerKey{}, slog.New(contextHandler))
+
+	jobs := make(chan Job[string], 1)
+	if err := Submit(ctx, jobs, "payload"); err != nil {
+		t.Fatalf("unexpected submit error: %v", err)
+	}
+
+	job := <-jobs
+	err := job.Run(func(ctx context.Context, payload string) error {
+		if payload != "payload" {
+			t.Errorf("expected payload 'payload', got %q", payload)
+		}
+		FromContext(ctx).InfoContext(ctx, "processing")
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected run error: %v", err)
+	}
+
+	if len(contextHandler.logs) != 1 {
+		t.Errorf("expected context logger to receive 1 log, got %d", len(contextHandler.logs))
+	}
+}
+
+func TestJobRunPanic(t *testing.T) {
+	contextHandler := newTestBufferHandler()
+	ctx := context.WithValue(context.Background(), ContextLoggerKey{}, slog.New(contextHandler))
+
+	err := NewJob(ctx, 42).Run(func(ctx context.Context, payload int) error {
+		panic("boom")
+	})
+	if !errors.Is(err, ErrJobPanic) {
+		t.Errorf("expected ErrJobPanic, got %v", err)
+	}
+
+	if len(contextHandler.logs) != 1 {
+		t.Fatalf("expected panic to be logged through the context logger, got %d logs", len(contextHandler.logs))
+	}
+	if log := contextHandler.logs[0]; !strings.HasPrefix(log, "ERROR: job panicked") || !strings.Contains(log, "panic=boom") {
+		t.Errorf("unexpected panic log: %q", log)
+	}
+}
+
+func TestSubmitCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	jobs := make(chan Job[int])
+	if err := Submit(ctx, jobs, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
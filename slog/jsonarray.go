@@ -0,0 +1,80 @@
+package slog
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// jsonArrayWriter is a writer for JSON handlers that writes the records as the
+// elements of a single JSON array instead of one object per line: the first
+// record opens the array, later records are preceded by a comma, and Close
+// closes the array, writing an empty one if no record was written.
+//
+// The array is only valid once Close has been called, so the destination must
+// be a file that is closed when the process is done logging (see Close).
+type jsonArrayWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	opened bool
+	closed bool
+}
+
+// newJSONArrayWriter creates a new writer that writes records to w as a JSON array.
+func newJSONArrayWriter(w io.Writer) *jsonArrayWriter {
+	return &jsonArrayWriter{w: w}
+}
+
+// Write implements io.Writer.
+// It writes each record, with the separator before it, in a single call.
+func (a *jsonArrayWriter) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	sep := ",\n"
+	if !a.opened {
+		sep = "[\n"
+	}
+	buf := make([]byte, 0, len(sep)+len(p))
+	buf = append(buf, sep...)
+	buf = append(buf, bytes.TrimSuffix(p, []byte("\n"))...)
+	if _, err := a.w.Write(buf); err != nil {
+		return 0, err
+	}
+	a.opened = true
+	return len(p), nil
+}
+
+// Close closes the array. Records written afterwards fail with io.ErrClosedPipe.
+func (a *jsonArrayWriter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+
+	end := "\n]\n"
+	if !a.opened {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(a.w, end)
+	return err
+}
+
+// hasJSONFileOutput reports whether config writes JSON to a file, directly or
+// as one of its outputs.
+func hasJSONFileOutput(config *Config) bool {
+	if len(config.Outputs) == 0 {
+		return config.HandlerType == "json" && config.WriterType == "file"
+	}
+	for _, o := range config.Outputs {
+		if o.HandlerType == "json" && o.WriterType == "file" {
+			return true
+		}
+	}
+	return false
+}
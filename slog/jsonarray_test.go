@@ -0,0 +1,118 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJSONArrayFile(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	clearEnvVars()
+	logPath := filepath.Join(t.TempDir(), "test.json")
+	t.Setenv(EnvLoggerHandler, "json")
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, logPath)
+	t.Setenv(EnvLoggerJSONArray, "true")
+
+	logger, err := Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("first", "n", 1)
+	logger.Info("second", "n", 2)
+	logger.Info("third", "n", 3)
+	if err := Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(data, &records); err != nil {
+		t.Fatalf("expected a valid JSON array, got %s: %v", data, err)
+	}
+	if len(records) != 3 || records[0]["msg"] != "first" || records[2]["n"] != float64(3) {
+		t.Errorf("unexpected records: %v", records)
+	}
+}
+
+func TestJSONArrayWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newJSONArrayWriter(&buf)
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("expected an empty array without records, got %q", buf.String())
+	}
+	if _, err := w.Write([]byte("{}\n")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("expected writes after Close to fail, got %v", err)
+	}
+
+	buf.Reset()
+	w = newJSONArrayWriter(&buf)
+	logger := slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{ReplaceAttr: removeTime}))
+	logger.Info("a")
+	logger.Info("b")
+	w.Close()
+	w.Close()
+	if expected := "[\n{\"level\":\"INFO\",\"msg\":\"a\"},\n{\"level\":\"INFO\",\"msg\":\"b\"}\n]\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestReadConfigJSONArray(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name  string
+		env   map[string]string
+		valid bool
+	}{
+		{
+			name:  "JSON File",
+			env:   map[string]string{EnvLoggerHandler: "json", EnvLoggerWriter: "file", EnvLoggerWriterFilePath: filepath.Join(dir, "a.json")},
+			valid: true,
+		},
+		{
+			name:  "JSON File Output",
+			env:   map[string]string{EnvLoggerOutputs: "text=stderr,json=file:" + filepath.Join(dir, "a.json")},
+			valid: true,
+		},
+		{
+			name: "Text File",
+			env:  map[string]string{EnvLoggerWriter: "file", EnvLoggerWriterFilePath: filepath.Join(dir, "a.log")},
+		},
+		{
+			name: "JSON Stdout",
+			env:  map[string]string{EnvLoggerHandler: "json", EnvLoggerWriter: "stdout"},
+		},
+		{
+			name: "Length Framing",
+			env:  map[string]string{EnvLoggerHandler: "json", EnvLoggerWriter: "file", EnvLoggerWriterFilePath: filepath.Join(dir, "a.json"), EnvLoggerFraming: "length"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.env[EnvLoggerJSONArray] = "true"
+			config, err := readConfigFromEnv(t, tt.env)
+			if tt.valid {
+				if err != nil || !config.JSONArray {
+					t.Errorf("expected JSON array output, got %v", err)
+				}
+			} else if !errors.Is(err, ErrInvalidJSONArray) {
+				t.Errorf("expected ErrInvalidJSONArray, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// lazyAttrsKey is the context key under which WithLazyAttrs keeps its functions.
+type lazyAttrsKey struct{}
+
+// WithLazyAttrs returns a copy of ctx that carries fn, which computes
+// attributes for the records logged with the returned context. Unlike the
+// attributes bound to the context logger with With or WithError, which are
+// computed once up front, fn is called each time a record is handled, with the
+// context of the logging call, so expensive lookups such as loading a session
+// cost nothing for records below the minimum level. Functions accumulate:
+// those of earlier WithLazyAttrs calls on ctx run first. A nil fn returns ctx
+// unchanged.
+//
+// The functions are called by the context-aware handler of the logger, so
+// they apply to the calls that pass a context, such as slog.InfoContext, made
+// through a logger built by this package. They run once the record has passed
+// the level check but before the other wrappers, so records dropped later, for
+// example by sampling, still cost a call.
+func WithLazyAttrs(ctx context.Context, fn func(context.Context) []slog.Attr) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	fns, _ := ctx.Value(lazyAttrsKey{}).([]func(context.Context) []slog.Attr)
+	return context.WithValue(ctx, lazyAttrsKey{}, append(slices.Clip(fns), fn))
+}
+
+// addLazyAttrs returns r with the attributes computed by the functions stored
+// in ctx by WithLazyAttrs added, or r itself if there are none.
+func addLazyAttrs(ctx context.Context, r slog.Record) slog.Record {
+	fns, _ := ctx.Value(lazyAttrsKey{}).([]func(context.Context) []slog.Attr)
+	if len(fns) == 0 {
+		return r
+	}
+	r = r.Clone()
+	for _, fn := range fns {
+		r.AddAttrs(fn(ctx)...)
+	}
+	return r
+}
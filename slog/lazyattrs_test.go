@@ -0,0 +1,62 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestWithLazyAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newContextAwareHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: removeTime})))
+
+	calls := 0
+	ctx := WithLazyAttrs(context.Background(), func(ctx context.Context) []slog.Attr {
+		calls++
+		return []slog.Attr{slog.String("session", "s-1")}
+	})
+	ctx = WithLazyAttrs(ctx, func(ctx context.Context) []slog.Attr {
+		return []slog.Attr{slog.Int("calls", calls)}
+	})
+	if WithLazyAttrs(ctx, nil) != ctx {
+		t.Error("expected a nil function to return ctx unchanged")
+	}
+
+	// The functions are not called for records below the minimum level
+	logger.DebugContext(ctx, "hidden")
+	if calls != 0 {
+		t.Errorf("expected no call for a disabled record, got %d", calls)
+	}
+
+	logger.InfoContext(ctx, "shown", "user", "alice")
+	logger.Info("no context")
+	expected := "level=INFO msg=shown user=alice session=s-1 calls=1\nlevel=INFO msg=\"no context\"\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWithLazyAttrsContextLogger(t *testing.T) {
+	defaultHandler := newAttrBufferHandler()
+	contextHandler := newAttrBufferHandler()
+	logger := slog.New(newContextAwareHandler(defaultHandler))
+
+	// The attributes are added once when the record is delegated to a
+	// context logger that is context-aware itself
+	calls := 0
+	ctx := WithContext(context.Background(), slog.New(newContextAwareHandler(contextHandler)))
+	ctx = WithLazyAttrs(ctx, func(ctx context.Context) []slog.Attr {
+		calls++
+		return []slog.Attr{slog.String("session", "s-1")}
+	})
+	logger.InfoContext(ctx, "delegated")
+
+	if expected := []string{"INFO: delegated [session=s-1]"}; !reflect.DeepEqual(contextHandler.logs, expected) || calls != 1 {
+		t.Errorf("expected %v from one call, got %v from %d", expected, contextHandler.logs, calls)
+	}
+	if len(defaultHandler.logs) != 0 {
+		t.Errorf("expected nothing in the default handler, got %v", defaultHandler.logs)
+	}
+}
@@ -0,0 +1,55 @@
+package slog
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// defaultLevel is the minimum level of the default logger installed by Init.
+// Init sets it to the configured level; a level provider may change it later.
+var defaultLevel slog.LevelVar
+
+var (
+	levelProviderMu sync.Mutex
+	levelProvider   *periodicTask
+)
+
+// SetLevelProvider makes the minimum level of the default logger installed
+// by Init follow an external source, for example a centralized configuration
+// service. fn is called right away and then every interval on a background
+// goroutine; whenever it returns ok, the level is set to the level it
+// returned, otherwise the level is left unchanged, for example when the
+// source is unreachable. fn must be safe to call from another goroutine.
+//
+// Setting a provider replaces the previous one, and passing a nil fn only
+// stops it. Polling stops on Close. A later Init resets the level to
+// LOGGER_LEVEL, and the provider overrides it again on its next call.
+func SetLevelProvider(fn func() (slog.Level, bool), interval time.Duration) {
+	levelProviderMu.Lock()
+	defer levelProviderMu.Unlock()
+	if levelProvider != nil {
+		levelProvider.stop()
+		levelProvider = nil
+	}
+	if fn == nil || interval <= 0 {
+		return
+	}
+
+	task := startPeriodic(interval, true, func() {
+		if level, ok := fn(); ok {
+			defaultLevel.Set(level)
+		}
+	})
+	levelProvider = task
+
+	registerCloser(closeStopTasks, func() error {
+		levelProviderMu.Lock()
+		defer levelProviderMu.Unlock()
+		task.stop()
+		if levelProvider == task {
+			levelProvider = nil
+		}
+		return nil
+	})
+}
@@ -0,0 +1,67 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSetLevelProvider(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	defer Close()
+
+	clearEnvVars()
+	t.Setenv(EnvLoggerLevel, "info")
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, filepath.Join(t.TempDir(), "test.log"))
+	Init()
+
+	var level atomic.Int64
+	var ok atomic.Bool
+	var calls atomic.Int64
+	SetLevelProvider(func() (slog.Level, bool) {
+		calls.Add(1)
+		return slog.Level(level.Load()), ok.Load()
+	}, time.Millisecond)
+
+	// The level is left unchanged while the provider reports no level
+	waitFor(t, func() bool { return calls.Load() >= 2 })
+	if Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatalf("expected the configured level while the provider reports no level")
+	}
+
+	// The level follows the provider mid-run
+	level.Store(int64(slog.LevelDebug))
+	ok.Store(true)
+	waitFor(t, func() bool { return Enabled(context.Background(), slog.LevelDebug) })
+
+	level.Store(int64(slog.LevelError))
+	waitFor(t, func() bool { return !Enabled(context.Background(), slog.LevelWarn) })
+
+	// Polling stops on Close
+	if err := Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stopped := calls.Load()
+	time.Sleep(10 * time.Millisecond)
+	if calls.Load() != stopped {
+		t.Errorf("expected no provider calls after Close, got %d more", calls.Load()-stopped)
+	}
+}
+
+// waitFor polls cond until it holds, failing the test after a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
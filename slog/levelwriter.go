@@ -0,0 +1,121 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxLevelWriterLine is the length in bytes at which the writers returned by
+// NewLevelWriter log an unterminated line without waiting for its newline.
+const MaxLevelWriterLine = 64 * 1024
+
+// levelWriter is an io.Writer that logs each line written to it as a record.
+type levelWriter struct {
+	ctx    context.Context
+	logger *slog.Logger
+	level  slog.Level
+
+	mu  sync.Mutex
+	buf []byte // unterminated line carried over to the next Write
+	max int    // length at which buf is logged
+}
+
+// NewLevelWriter returns an io.Writer that logs each line written to it as a
+// record at the given level through logger, which bridges libraries that
+// report diagnostics to an io.Writer into slog. If logger is nil, the default
+// logger at the time of each write is used.
+//
+// Writes are split into lines; a line may span several writes and is logged
+// once its newline is written. Lines of MaxLevelWriterLine bytes or more are
+// logged in pieces of at most that length, as soon as each piece is complete,
+// so that output without newlines does not accumulate in memory.
+// Trailing carriage returns and empty lines are dropped. The returned writer also implements io.Closer, whose Close logs the
+// unterminated rest, if any. Records carry no source position, since the
+// caller of Write is rarely meaningful.
+func NewLevelWriter(logger *slog.Logger, level slog.Level) io.Writer {
+	return NewLevelWriterContext(context.Background(), logger, level)
+}
+
+// NewLevelWriterContext is like NewLevelWriter but logs with ctx, so that a
+// context logger stored in ctx is honored when the logger is context-aware.
+func NewLevelWriterContext(ctx context.Context, logger *slog.Logger, level slog.Level) io.Writer {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &levelWriter{ctx: ctx, logger: logger, level: level, max: MaxLevelWriterLine}
+}
+
+// Write implements io.Writer. It always consumes all of p.
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			w.flushLong()
+			break
+		}
+		w.buf = append(w.buf, p[:i]...)
+		w.flushLong()
+		if len(w.buf) > 0 {
+			w.log(w.buf)
+			w.buf = w.buf[:0]
+		}
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// flushLong logs the buffered line in pieces of at most w.max bytes while it
+// is at least that long.
+func (w *levelWriter) flushLong() {
+	for len(w.buf) >= w.max {
+		// Cut at a rune boundary so that the records stay valid UTF-8.
+		cut := w.max
+		for cut > 0 && cut < len(w.buf) && !utf8.RuneStart(w.buf[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			cut = w.max
+		}
+		w.log(w.buf[:cut])
+		w.buf = append(w.buf[:0], w.buf[cut:]...)
+	}
+}
+
+// Close implements io.Closer. It logs the unterminated rest, if any.
+func (w *levelWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.log(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+// log logs one line, stripped of a trailing carriage return.
+func (w *levelWriter) log(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(line) == 0 {
+		return
+	}
+
+	logger := w.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	handler := logger.Handler()
+	if !handler.Enabled(w.ctx, w.level) {
+		return
+	}
+	_ = handler.Handle(w.ctx, slog.NewRecord(time.Now(), w.level, string(line), 0))
+}
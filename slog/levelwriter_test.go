@@ -0,0 +1,91 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// messages returns the levels and messages of the records handled by h.
+func (h *recordingHandler) messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var msgs []string
+	for _, r := range h.records {
+		msgs = append(msgs, fmt.Sprintf("%v %s", r.Level, r.Message))
+	}
+	return msgs
+}
+
+func TestLevelWriter(t *testing.T) {
+	h := &recordingHandler{}
+	w := NewLevelWriter(slog.New(h), slog.LevelWarn)
+
+	// Lines split across writes, several lines per write and CRLF endings
+	for _, s := range []string{"first li", "ne\nsecond line\r\n\nthird", " line\n", "rest"} {
+		if n, err := io.WriteString(w, s); err != nil || n != len(s) {
+			t.Fatalf("Write(%q) = %d, %v", s, n, err)
+		}
+	}
+
+	expected := []string{"WARN first line", "WARN second line", "WARN third line"}
+	if got := h.messages(); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	if err := w.(io.Closer).Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected = append(expected, "WARN rest")
+	if got := h.messages(); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected the rest to be logged on Close, got %q", got)
+	}
+}
+
+func TestLevelWriterMaxLine(t *testing.T) {
+	h := &recordingHandler{}
+	w := NewLevelWriter(slog.New(h), slog.LevelWarn).(*levelWriter)
+	w.max = 4
+
+	// A line without newline is logged in pieces once it reaches the cap,
+	// cut before the multi-byte rune, and the rest is carried over
+	for _, s := range []string{"ab", "cdefg", "héllo\n"} {
+		io.WriteString(w, s)
+	}
+
+	expected := []string{"WARN abcd", "WARN efgh", "WARN éll", "WARN o"}
+	if got := h.messages(); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if len(w.buf) != 0 {
+		t.Errorf("expected an empty buffer, got %q", w.buf)
+	}
+}
+
+func TestLevelWriterDisabledLevel(t *testing.T) {
+	h := &recordingHandler{level: slog.LevelInfo}
+	w := NewLevelWriter(slog.New(h), slog.LevelDebug)
+	io.WriteString(w, "hidden\n")
+
+	if got := h.messages(); len(got) != 0 {
+		t.Errorf("expected no records below the handler level, got %q", got)
+	}
+}
+
+func TestLevelWriterContext(t *testing.T) {
+	base := &recordingHandler{}
+	ctxHandler := &recordingHandler{}
+	ctx := WithContext(context.Background(), slog.New(ctxHandler))
+
+	w := NewLevelWriterContext(ctx, slog.New(newContextAwareHandler(base)), slog.LevelInfo)
+	io.WriteString(w, "from library\n")
+
+	if got := base.messages(); len(got) != 0 {
+		t.Errorf("expected no records on the base handler, got %q", got)
+	}
+	if got := ctxHandler.messages(); len(got) != 1 || got[0] != "INFO from library" {
+		t.Errorf("expected the record on the context logger, got %q", got)
+	}
+}
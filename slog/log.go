@@ -0,0 +1,55 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// Enabled reports whether a context-aware log at the given level made with ctx,
+// such as slog.InfoContext(ctx, ...), would be handled, so that expensive log
+// construction can be skipped. It consults the default logger's handler, which
+// switches to the context logger stored in ctx when it is context-aware.
+func Enabled(ctx context.Context, level slog.Level) bool {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return slog.Default().Handler().Enabled(ctx, level)
+}
+
+// LogAt logs a record with the given timestamp instead of the current time,
+// which is useful for importers and backfills of historical events. The record
+// is dispatched through the default logger, so a context logger stored in ctx
+// is honored when the default logger is context-aware.
+func LogAt(ctx context.Context, t time.Time, level slog.Level, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	handler := slog.Default().Handler()
+	if !handler.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:]) // skip [runtime.Callers, LogAt]
+	r := slog.NewRecord(t, level, msg, pcs[0])
+	r.Add(args...)
+	_ = handler.Handle(ctx, r)
+}
+
+// Emit sends a pre-built record straight to the default logger's handler, for
+// example a record deserialized by a log proxy or received over a length-framed
+// transport. The record is dropped without error if the handler is not enabled
+// for its level. The record's time, PC and attributes are kept as they are, and
+// a context logger stored in ctx is honored as with LogAt.
+func Emit(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	handler := slog.Default().Handler()
+	if !handler.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return handler.Handle(ctx, r)
+}
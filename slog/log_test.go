@@ -0,0 +1,130 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestLogAt(t *testing.T) {
+	// Save the original default logger and restore it after the test
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	var defaultBuf, contextBuf bytes.Buffer
+	slog.SetDefault(slog.New(newContextAwareHandler(slog.NewJSONHandler(&defaultBuf, nil))))
+	contextLogger := slog.New(slog.NewJSONHandler(&contextBuf, nil))
+	ctxWithLogger := context.WithValue(context.Background(), ContextLoggerKey{}, contextLogger)
+
+	at := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		buf  *bytes.Buffer
+	}{
+		{name: "Default Logger", ctx: context.Background(), buf: &defaultBuf},
+		{name: "Context Logger", ctx: ctxWithLogger, buf: &contextBuf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultBuf.Reset()
+			contextBuf.Reset()
+
+			LogAt(tt.ctx, at, slog.LevelWarn, "historical event", "id", 1)
+
+			var record struct {
+				Time  time.Time `json:"time"`
+				Level string    `json:"level"`
+				Msg   string    `json:"msg"`
+				ID    int       `json:"id"`
+			}
+			if err := json.Unmarshal(tt.buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to parse output %q: %v", tt.buf.String(), err)
+			}
+			if !record.Time.Equal(at) {
+				t.Errorf("expected time %v, got %v", at, record.Time)
+			}
+			if record.Level != "WARN" || record.Msg != "historical event" || record.ID != 1 {
+				t.Errorf("unexpected record: %+v", record)
+			}
+		})
+	}
+
+	// Records below the default logger's level are not logged
+	defaultBuf.Reset()
+	LogAt(context.Background(), at, slog.LevelDebug, "suppressed")
+	if defaultBuf.Len() != 0 {
+		t.Errorf("expected no output for disabled level, got %q", defaultBuf.String())
+	}
+}
+
+func TestEmit(t *testing.T) {
+	// Save the original default logger and restore it after the test
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(newContextAwareHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))))
+
+	at := time.Date(2020, time.January, 2, 3, 4, 5, 0, time.UTC)
+	r := slog.NewRecord(at, slog.LevelError, "forwarded", 0)
+	r.AddAttrs(slog.String("origin", "edge-1"), slog.Group("req", slog.Int("status", 500)))
+
+	if err := Emit(context.Background(), r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	if record["time"] != "2020-01-02T03:04:05Z" || record["level"] != "ERROR" || record["msg"] != "forwarded" ||
+		record["origin"] != "edge-1" || record["req"].(map[string]any)["status"] != float64(500) {
+		t.Errorf("unexpected record %v", record)
+	}
+
+	// Records below the handler's level are dropped
+	buf.Reset()
+	if err := Emit(context.Background(), slog.NewRecord(at, slog.LevelDebug, "debug", 0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for a disabled level, got %q", buf.String())
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	// Save the original default logger and restore it after the test
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	slog.SetDefault(slog.New(newContextAwareHandler(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelWarn}))))
+	contextLogger := slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctxWithLogger := WithContext(context.Background(), contextLogger)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		level    slog.Level
+		expected bool
+	}{
+		{name: "Default Logger Below Level", ctx: context.Background(), level: slog.LevelInfo, expected: false},
+		{name: "Default Logger At Level", ctx: context.Background(), level: slog.LevelWarn, expected: true},
+		{name: "Nil Context", ctx: nil, level: slog.LevelError, expected: true},
+		{name: "Context Logger", ctx: ctxWithLogger, level: slog.LevelDebug, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Enabled(tt.ctx, tt.level); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
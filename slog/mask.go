@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// MaskReplacement replaces the matches of the mask patterns.
+const MaskReplacement = "[MASKED]"
+
+// parseMaskPatterns compiles the whitespace-separated regular expressions of
+// LOGGER_MASK_PATTERNS. Patterns are separated by whitespace rather than commas
+// because commas are common in repetition counts such as \d{13,16}; use \s or
+// \x20 to match a space.
+func parseMaskPatterns(s string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, expr := range strings.Fields(s) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidMaskPattern, expr, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+// replaceMaskPatterns returns a ReplaceAttr function that replaces the matches
+// of the config's mask patterns in the message and in string attribute values
+// with MaskReplacement, or nil if no mask patterns are configured.
+//
+// Every string value is scanned with every pattern, so the cost of logging
+// grows with the number of patterns and the length of the values. Values of
+// other kinds, such as errors and fmt.Stringers, are not masked, and neither
+// are the other built-in attributes.
+func replaceMaskPatterns(config *Config) func([]string, slog.Attr) slog.Attr {
+	if len(config.MaskPatterns) == 0 {
+		return nil
+	}
+
+	patterns := config.MaskPatterns
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() != slog.KindString || (len(groups) == 0 && isBuiltinKey(a.Key) && a.Key != slog.MessageKey) {
+			return a
+		}
+		s := a.Value.String()
+		masked := s
+		for _, re := range patterns {
+			masked = re.ReplaceAllLiteralString(masked, MaskReplacement)
+		}
+		if masked != s {
+			a.Value = slog.StringValue(masked)
+		}
+		return a
+	}
+}
@@ -0,0 +1,46 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestReplaceMaskPatterns(t *testing.T) {
+	patterns, err := parseMaskPatterns(`\b\d{4}([\s-]?\d{4}){3}\b [\w.+-]+@[\w-]+\.[\w.]+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := &Config{HandlerType: "bare", MaskPatterns: patterns}
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(config, &buf))
+
+	logger.Info("charged 4111 1111 1111 1111 for alice@example.com",
+		"card", "4111-1111-1111-1111", "note", "contact bob@example.org or carol@example.net",
+		slog.Group("user", "email", "dave@example.com"), "amount", 4111111111111111, "order", "A-1234")
+
+	expected := `msg="charged [MASKED] for [MASKED]" card=[MASKED] note="contact [MASKED] or [MASKED]" user.email=[MASKED] amount=4111111111111111 order=A-1234` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestReadConfigMaskPatterns(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerMaskPatterns: `\d{13,16}   secret-\w+`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var exprs []string
+	for _, re := range config.MaskPatterns {
+		exprs = append(exprs, re.String())
+	}
+	if strings.Join(exprs, " ") != `\d{13,16} secret-\w+` {
+		t.Errorf("unexpected mask patterns: %q", exprs)
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerMaskPatterns: `ok (unclosed`}); !errors.Is(err, ErrInvalidMaskPattern) {
+		t.Errorf("expected ErrInvalidMaskPattern, got %v", err)
+	}
+}
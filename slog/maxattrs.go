@@ -0,0 +1,84 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// OverflowKey is the key of the attribute that counts the attributes trimmed
+// by LOGGER_MAX_ATTRS.
+const OverflowKey = "_overflow"
+
+// maxAttrsHandler is a wrapper handler that caps the number of attributes per
+// record, counting the attributes added with WithAttrs as well as those of the
+// record. Attributes are kept in output order, WithAttrs attributes first, and
+// those beyond the limit are replaced by a single OverflowKey attribute holding
+// their number. A group counts as one attribute. The overflow attribute is
+// added at the record level, so it lands inside any group opened with WithGroup.
+type maxAttrsHandler struct {
+	next     slog.Handler
+	limit    int
+	used     int // attributes passed to next with WithAttrs
+	overflow int // attributes trimmed from WithAttrs
+}
+
+// newMaxAttrsHandler creates a new handler that keeps at most limit attributes per record.
+func newMaxAttrsHandler(next slog.Handler, limit int) slog.Handler {
+	return &maxAttrsHandler{next: next, limit: limit}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *maxAttrsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *maxAttrsHandler) Handle(ctx context.Context, r slog.Record) error {
+	remaining := h.limit - h.used
+	if h.overflow == 0 && r.NumAttrs() <= remaining {
+		return h.next.Handle(ctx, r)
+	}
+
+	trimmed := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	overflow := h.overflow
+	r.Attrs(func(a slog.Attr) bool {
+		if remaining > 0 {
+			trimmed.AddAttrs(a)
+			remaining--
+		} else {
+			overflow++
+		}
+		return true
+	})
+	trimmed.AddAttrs(slog.Int(OverflowKey, overflow))
+	return h.next.Handle(ctx, trimmed)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *maxAttrsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	keep := min(len(attrs), h.limit-h.used)
+	h2.overflow += len(attrs) - keep
+	h2.used += keep
+	if keep > 0 {
+		h2.next = h.next.WithAttrs(attrs[:keep])
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *maxAttrsHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	return &h2
+}
+
+// describe implements describer.
+func (h *maxAttrsHandler) describe() string {
+	return "max_attrs"
+}
+
+// unwrap implements describer.
+func (h *maxAttrsHandler) unwrap() slog.Handler {
+	return h.next
+}
@@ -0,0 +1,81 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMaxAttrsHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(logger *slog.Logger)
+		expected string
+	}{
+		{
+			name:     "under the limit",
+			log:      func(logger *slog.Logger) { logger.Info("test", "a", 1, "b", 2) },
+			expected: "msg=test a=1 b=2",
+		},
+		{
+			name:     "at the limit",
+			log:      func(logger *slog.Logger) { logger.Info("test", "a", 1, "b", 2, "c", 3) },
+			expected: "msg=test a=1 b=2 c=3",
+		},
+		{
+			name:     "over the limit",
+			log:      func(logger *slog.Logger) { logger.Info("test", "a", 1, "b", 2, "c", 3, "d", 4, "e", 5) },
+			expected: "msg=test a=1 b=2 c=3 _overflow=2",
+		},
+		{
+			name:     "with attrs count first",
+			log:      func(logger *slog.Logger) { logger.With("a", 1, "b", 2).Info("test", "c", 3, "d", 4) },
+			expected: "msg=test a=1 b=2 c=3 _overflow=1",
+		},
+		{
+			name:     "with attrs over the limit",
+			log:      func(logger *slog.Logger) { logger.With("a", 1, "b", 2).With("c", 3, "d", 4).Info("test", "e", 5) },
+			expected: "msg=test a=1 b=2 c=3 _overflow=2",
+		},
+		{
+			name:     "group counts as one",
+			log:      func(logger *slog.Logger) { logger.Info("test", slog.Group("g", "a", 1, "b", 2, "c", 3, "d", 4)) },
+			expected: "msg=test g.a=1 g.b=2 g.c=3 g.d=4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(createHandler(&Config{HandlerType: "bare", MaxAttrs: 3}, &buf))
+
+			tt.log(logger)
+
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestReadConfigMaxAttrs(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerMaxAttrs: "20"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.MaxAttrs != 20 {
+		t.Errorf("expected 20, got %d", config.MaxAttrs)
+	}
+
+	for _, value := range []string{"0", "-1", "many"} {
+		if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerMaxAttrs: value}); !errors.Is(err, ErrInvalidMaxAttrs) {
+			t.Errorf("%q: expected ErrInvalidMaxAttrs, got %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package slog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// MinMaxLineBytes is the smallest line length limit accepted for
+// LOGGER_MAX_LINE_BYTES, leaving room for the header of split lines.
+const MinMaxLineBytes = 64
+
+// splitIDs numbers the records split by maxLineWriters.
+var splitIDs atomic.Uint64
+
+// maxLineWriter enforces a maximum length on the lines written to w. slog
+// handlers write each encoded record, ending in a newline, with a single
+// call, so every write is checked as a whole. Records that fit, counting the
+// newline, are passed through unchanged. Longer records are handled according
+// to action:
+//
+//   - "truncate" cuts the record to the limit, keeping its final newline and
+//     not splitting a UTF-8 encoded character. The
+//     truncated line is no longer valid JSON for the json handler.
+//   - "drop" discards the record and reports it to the drop callback with
+//     DropReasonTooLarge. Since the check runs on the encoded bytes, the record
+//     passed to the callback only carries the encoded size in a "bytes"
+//     attribute.
+//   - "split" writes the record as consecutive lines of at most the limit,
+//     each holding the header "split <id> <part>/<parts>: " followed by the
+//     next chunk of the record without its newline. The id, in hex, is shared
+//     by the parts of one record, and the parts are numbered from 1, so
+//     consumers can reassemble the record by concatenating the chunks.
+//     The parts are written with a single call so that they are not
+//     interleaved with other records.
+type maxLineWriter struct {
+	w      io.Writer
+	max    int
+	action string
+}
+
+// newMaxLineWriter creates a writer that enforces a line length limit of max
+// bytes on w with the given action.
+func newMaxLineWriter(w io.Writer, max int, action string) io.Writer {
+	return &maxLineWriter{w: w, max: max, action: action}
+}
+
+// Write implements io.Writer.
+func (m *maxLineWriter) Write(p []byte) (int, error) {
+	if len(p) <= m.max {
+		return m.w.Write(p)
+	}
+
+	switch m.action {
+	case "drop":
+		r := slog.NewRecord(time.Now(), slog.LevelInfo, "record too large", 0)
+		r.AddAttrs(slog.Int("bytes", len(p)))
+		reportDrop(r, DropReasonTooLarge)
+		return len(p), nil
+	case "split":
+		if _, err := m.w.Write(splitLine(p, m.max)); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	default:
+		// Cut at a rune boundary so that the line stays valid UTF-8.
+		cut := m.max - 1
+		for cut > 0 && !utf8.RuneStart(p[cut]) {
+			cut--
+		}
+		line := make([]byte, cut+1)
+		copy(line, p[:cut])
+		line[cut] = '\n'
+		if _, err := m.w.Write(line); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+}
+
+// splitLine splits the record p into lines of at most max bytes in the format
+// described on maxLineWriter.
+func splitLine(p []byte, max int) []byte {
+	p = bytes.TrimSuffix(p, []byte("\n"))
+	id := strconv.FormatUint(splitIDs.Add(1), 16)
+
+	// The header length depends on the number of parts, which depends on the
+	// room left by the header, so iterate until the count is stable.
+	parts := 1
+	for {
+		room := max - len(splitHeader(id, parts, parts)) - 1
+		n := (len(p) + room - 1) / room
+		if n <= parts {
+			break
+		}
+		parts = n
+	}
+
+	var buf bytes.Buffer
+	for part := 1; part <= parts; part++ {
+		header := splitHeader(id, part, parts)
+		room := max - len(splitHeader(id, parts, parts)) - 1
+		chunk := p[:min(room, len(p))]
+		p = p[len(chunk):]
+		buf.WriteString(header)
+		buf.Write(chunk)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// splitHeader returns the header of a split line.
+func splitHeader(id string, part, parts int) string {
+	return fmt.Sprintf("split %s %d/%d: ", id, part, parts)
+}
@@ -0,0 +1,126 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMaxLineWriterBoundary(t *testing.T) {
+	fits := strings.Repeat("a", 63) + "\n"
+	for _, action := range []string{"truncate", "drop", "split"} {
+		var buf bytes.Buffer
+		w := newMaxLineWriter(&buf, 64, action)
+		if n, err := w.Write([]byte(fits)); err != nil || n != len(fits) {
+			t.Fatalf("%s: Write = %d, %v", action, n, err)
+		}
+		if buf.String() != fits {
+			t.Errorf("%s: expected a record at the limit to pass unchanged, got %q", action, buf.String())
+		}
+	}
+}
+
+func TestMaxLineWriterTruncate(t *testing.T) {
+	var buf bytes.Buffer
+	w := newMaxLineWriter(&buf, 64, "truncate")
+	record := strings.Repeat("a", 64) + "\n"
+	if n, err := w.Write([]byte(record)); err != nil || n != len(record) {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if expected := strings.Repeat("a", 63) + "\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	// A multi-byte character at the cut is dropped whole
+	buf.Reset()
+	w.Write([]byte(strings.Repeat("a", 62) + "éé\n"))
+	if expected := strings.Repeat("a", 62) + "\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestMaxLineWriterDrop(t *testing.T) {
+	defer SetDropCallback(nil)
+	var reasons []string
+	var size int64
+	SetDropCallback(func(r slog.Record, reason string) {
+		reasons = append(reasons, reason)
+		r.Attrs(func(a slog.Attr) bool {
+			size = a.Value.Int64()
+			return true
+		})
+	})
+
+	var buf bytes.Buffer
+	w := newMaxLineWriter(&buf, 64, "drop")
+	record := strings.Repeat("a", 64) + "\n"
+	if n, err := w.Write([]byte(record)); err != nil || n != len(record) {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected the record to be dropped, got %q", buf.String())
+	}
+	if len(reasons) != 1 || reasons[0] != DropReasonTooLarge || size != 65 {
+		t.Errorf("expected one too_large drop of 65 bytes, got %v (%d bytes)", reasons, size)
+	}
+}
+
+func TestMaxLineWriterSplit(t *testing.T) {
+	var buf bytes.Buffer
+	w := newMaxLineWriter(&buf, 64, "split")
+	record := strings.Repeat("0123456789", 10) + "\n"
+	if n, err := w.Write([]byte(record)); err != nil || n != len(record) {
+		t.Fatalf("Write = %d, %v", n, err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected several parts, got %q", buf.String())
+	}
+	var id, joined string
+	for i, line := range lines {
+		if len(line)+1 > 64 {
+			t.Errorf("part %d exceeds the limit: %q", i+1, line)
+		}
+		header, chunk, ok := strings.Cut(line, ": ")
+		fields := strings.Fields(header)
+		if !ok || len(fields) != 3 || fields[0] != "split" {
+			t.Fatalf("unexpected part %q", line)
+		}
+		if i == 0 {
+			id = fields[1]
+		} else if fields[1] != id {
+			t.Errorf("expected the parts to share id %q, got %q", id, fields[1])
+		}
+		if expected := strconv.Itoa(i+1) + "/" + strconv.Itoa(len(lines)); fields[2] != expected {
+			t.Errorf("expected part %s, got %s", expected, fields[2])
+		}
+		joined += chunk
+	}
+	if joined+"\n" != record {
+		t.Errorf("expected the parts to reassemble the record, got %q", joined)
+	}
+}
+
+func TestReadConfigMaxLine(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerMaxLineBytes: "1024"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.MaxLineBytes != 1024 || config.MaxLineAction != "truncate" {
+		t.Errorf("unexpected settings: %d %q", config.MaxLineBytes, config.MaxLineAction)
+	}
+
+	for _, env := range []map[string]string{
+		{EnvLoggerMaxLineBytes: "63"},
+		{EnvLoggerMaxLineBytes: "big"},
+		{EnvLoggerMaxLineBytes: "1024", EnvLoggerMaxLineAction: "wrap"},
+	} {
+		if _, err := readConfigFromEnv(t, env); !errors.Is(err, ErrInvalidMaxLine) {
+			t.Errorf("%v: expected ErrInvalidMaxLine, got %v", env, err)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Middleware wraps a handler with another handler, for example one that
+// redacts attributes or adds extra ones.
+type Middleware func(next slog.Handler) slog.Handler
+
+// WithMiddleware returns a copy of ctx whose context logger wraps its handler
+// with mw, so that mw applies to the records logged through ctx for the
+// remainder of the context. The context logger is based on the logger already
+// stored in ctx, or slog.Default(). A nil mw returns ctx unchanged.
+//
+// Attributes and groups already bound to the context logger, for example with
+// WithContext(ctx, logger.With(...)) or Scope, are applied by the wrapped
+// handler and thus are not seen by mw. Attributes and groups added to the
+// returned context logger afterwards pass through mw's WithAttrs and
+// WithGroup.
+func WithMiddleware(ctx context.Context, mw Middleware) context.Context {
+	if mw == nil {
+		return ctx
+	}
+	return WithContext(ctx, newContextLogger(mw(FromContext(ctx).Handler())))
+}
@@ -0,0 +1,109 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+)
+
+// redactHandler is a test middleware that redacts "password" attributes.
+type redactHandler struct {
+	slog.Handler
+}
+
+func (h redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(redactPassword(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, redacted)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactPassword(a)
+	}
+	return redactHandler{h.Handler.WithAttrs(redacted)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{h.Handler.WithGroup(name)}
+}
+
+func redactPassword(a slog.Attr) slog.Attr {
+	if a.Key == "password" {
+		return slog.String(a.Key, "[REDACTED]")
+	}
+	return a
+}
+
+func TestWithMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare", Level: slog.LevelInfo}, &buf))
+	ctx := WithContext(context.Background(), logger.With("RequestID", "req-1"))
+
+	redactCtx := WithMiddleware(ctx, func(next slog.Handler) slog.Handler {
+		return redactHandler{next}
+	})
+	FromContext(redactCtx).InfoContext(redactCtx, "login", "password", "hunter2")
+	// Attributes added after the middleware pass through it
+	FromContext(redactCtx).With("password", "hunter2").Info("retry")
+	// Other contexts are not affected
+	FromContext(ctx).InfoContext(ctx, "login", "password", "hunter2")
+
+	if WithMiddleware(ctx, nil) != ctx {
+		t.Error("expected a nil middleware to return ctx unchanged")
+	}
+
+	expected := "msg=login RequestID=req-1 password=[REDACTED]\nmsg=retry RequestID=req-1 password=[REDACTED]\nmsg=login RequestID=req-1 password=hunter2\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+// countHandler is a test middleware that counts the records it handles and
+// adds an attribute to them.
+type countHandler struct {
+	slog.Handler
+	count *int
+}
+
+func (h countHandler) Handle(ctx context.Context, r slog.Record) error {
+	*h.count++
+	r.AddAttrs(slog.Int("extra", 1))
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h countHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return countHandler{h.Handler.WithAttrs(attrs), h.count}
+}
+
+func (h countHandler) WithGroup(name string) slog.Handler {
+	return countHandler{h.Handler.WithGroup(name), h.count}
+}
+
+func TestWithMiddlewareOnce(t *testing.T) {
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(createHandler(&Config{HandlerType: "bare", Level: slog.LevelInfo}, &buf)))
+
+	var count int
+	ctx := WithMiddleware(context.Background(), func(next slog.Handler) slog.Handler {
+		return countHandler{next, &count}
+	})
+	FromContext(ctx).InfoContext(ctx, "direct")
+	FromContext(ctx).With("k", "v").InfoContext(ctx, "derived")
+	slog.InfoContext(ctx, "default")
+
+	if count != 3 {
+		t.Errorf("expected the middleware to see each of 3 records once, got %d", count)
+	}
+	expected := "msg=direct extra=1\nmsg=derived k=v extra=1\nmsg=default extra=1\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
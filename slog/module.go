@@ -0,0 +1,93 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// moduleHandler is a wrapper handler that adds the import path of the
+// package of the logging call to each record as a "module" attribute, so that
+// records can be grouped by package without tagging them by hand.
+//
+// The package is derived from the function name of the record's program
+// counter. Resolving it costs a symbol lookup, so the result is cached per
+// program counter; the cache grows with the number of logging call sites.
+// Records without a program counter are left as they are.
+type moduleHandler struct {
+	next slog.Handler
+}
+
+// moduleCache maps program counters to the import paths of their packages.
+var moduleCache sync.Map
+
+// newModuleHandler creates a new handler that adds the package of the logging call to each record.
+func newModuleHandler(next slog.Handler) slog.Handler {
+	return &moduleHandler{next: next}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *moduleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *moduleHandler) Handle(ctx context.Context, r slog.Record) error {
+	if module := pcModule(r.PC); module != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String("module", module))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *moduleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &moduleHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *moduleHandler) WithGroup(name string) slog.Handler {
+	return &moduleHandler{next: h.next.WithGroup(name)}
+}
+
+// describe implements describer.
+func (h *moduleHandler) describe() string {
+	return "module"
+}
+
+// unwrap implements describer.
+func (h *moduleHandler) unwrap() slog.Handler {
+	return h.next
+}
+
+// pcModule returns the import path of the package of the function at pc, or
+// "" if it cannot be determined.
+func pcModule(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+	if module, ok := moduleCache.Load(pc); ok {
+		return module.(string)
+	}
+
+	// CallersFrames rather than FuncForPC resolves the function that was
+	// inlined at pc, as slog does for the source position.
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	module := funcPackage(frame.Function)
+	moduleCache.Store(pc, module)
+	return module
+}
+
+// funcPackage returns the import path of the package of the fully qualified
+// function name, such as "example.com/app/db" for "example.com/app/db.(*DB).Query".
+// Dots in the last element of the path are escaped as "%2e" in function
+// names, so the first dot after the last slash ends the path.
+func funcPackage(name string) string {
+	slash := strings.LastIndexByte(name, '/') + 1
+	if dot := strings.IndexByte(name[slash:], '.'); dot >= 0 {
+		name = name[:slash+dot]
+	}
+	return strings.ReplaceAll(name, "%2e", ".")
+}
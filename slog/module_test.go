@@ -0,0 +1,55 @@
+package slog
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestModuleHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newModuleHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: removeTime})))
+
+	logger.Info("called")
+	expected := "level=INFO msg=called module=github.com/nakat-t/planks-go/slog\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	// Records without a program counter are left as they are
+	buf.Reset()
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "no pc", 0)
+	if err := logger.Handler().Handle(t.Context(), r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "level=INFO msg=\"no pc\"\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestFuncPackage(t *testing.T) {
+	tests := map[string]string{
+		"main.main":                      "main",
+		"example.com/app/db.(*DB).Query": "example.com/app/db",
+		"example.com/app/db.Open.func1":  "example.com/app/db",
+		"example.com/app.v2/x.F":         "example.com/app.v2/x",
+		"gopkg.in/yaml%2ev3.Unmarshal":   "gopkg.in/yaml.v3",
+		"runtime":                        "runtime",
+	}
+	for name, expected := range tests {
+		if got := funcPackage(name); got != expected {
+			t.Errorf("funcPackage(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
+
+func TestReadConfigAddModule(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerAddModule: "true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.AddModule {
+		t.Error("expected AddModule to be enabled")
+	}
+}
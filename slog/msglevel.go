@@ -0,0 +1,135 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// MsgLevelOverride changes the level of the records whose message contains
+// Substring to Level.
+type MsgLevelOverride struct {
+	Substring string
+	Level     slog.Level
+}
+
+// String returns the override in the syntax of LOGGER_MSG_LEVEL_OVERRIDES.
+func (o MsgLevelOverride) String() string {
+	return o.Substring + "=" + o.Level.String()
+}
+
+// parseMsgLevelOverrides parses the value of LOGGER_MSG_LEVEL_OVERRIDES, a
+// comma-separated list of "substring=level" entries. The level follows the
+// last "=" of an entry, so the substring may contain "=" itself. Spaces
+// around the substring and the level are ignored.
+func parseMsgLevelOverrides(s string) ([]MsgLevelOverride, error) {
+	var overrides []MsgLevelOverride
+	for _, entry := range splitList(s) {
+		i := strings.LastIndexByte(entry, '=')
+		substring := strings.TrimSpace(entry[:max(i, 0)])
+		if substring == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMsgLevelOverride, entry)
+		}
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(entry[i+1:]))); err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidMsgLevelOverride, entry, err)
+		}
+		overrides = append(overrides, MsgLevelOverride{Substring: substring, Level: level})
+	}
+	return overrides, nil
+}
+
+// matchMsgLevel returns the level of the first override whose substring the
+// message contains.
+func matchMsgLevel(overrides []MsgLevelOverride, msg string) (slog.Level, bool) {
+	for _, o := range overrides {
+		if strings.Contains(msg, o.Substring) {
+			return o.Level, true
+		}
+	}
+	return 0, false
+}
+
+// LogWithLevelOverride logs a record like LogAt at the current time, but with
+// the level given by the first LOGGER_MSG_LEVEL_OVERRIDES entry matching msg,
+// or level if none matches. The override is resolved before the level check,
+// so a message can be promoted from below the minimum level, which the
+// overrides of ordinary logging calls cannot do. The overrides are those of
+// the configuration most recently applied by Init.
+func LogWithLevelOverride(ctx context.Context, level slog.Level, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if config := currentConfig.Load(); config != nil {
+		if override, ok := matchMsgLevel(config.MsgLevelOverrides, msg); ok {
+			level = override
+		}
+	}
+	handler := slog.Default().Handler()
+	if !handler.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:]) // skip [runtime.Callers, LogWithLevelOverride]
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = handler.Handle(ctx, r)
+}
+
+// msgLevelHandler is a wrapper handler that changes the level of records
+// whose message matches one of its overrides.
+//
+// It only sees the records enabled at their original level: records logged
+// below the minimum level are discarded before a message exists to match, so
+// they cannot be promoted except through LogWithLevelOverride. Records demoted
+// below the minimum level are dropped.
+type msgLevelHandler struct {
+	next      slog.Handler
+	overrides []MsgLevelOverride
+}
+
+// newMsgLevelHandler creates a new handler that applies the given message level overrides.
+func newMsgLevelHandler(next slog.Handler, overrides []MsgLevelOverride) slog.Handler {
+	return &msgLevelHandler{next: next, overrides: overrides}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *msgLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *msgLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if level, ok := matchMsgLevel(h.overrides, r.Message); ok && level != r.Level {
+		// A suppressHandler counts the records it drops by level itself.
+		if _, counts := h.next.(*suppressHandler); !counts && !h.next.Enabled(ctx, level) {
+			return nil
+		}
+		r.Level = level
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *msgLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &msgLevelHandler{next: h.next.WithAttrs(attrs), overrides: h.overrides}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *msgLevelHandler) WithGroup(name string) slog.Handler {
+	return &msgLevelHandler{next: h.next.WithGroup(name), overrides: h.overrides}
+}
+
+// describe implements describer.
+func (h *msgLevelHandler) describe() string {
+	return "msg_level"
+}
+
+// unwrap implements describer.
+func (h *msgLevelHandler) unwrap() slog.Handler {
+	return h.next
+}
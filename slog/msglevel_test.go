@@ -0,0 +1,84 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestParseMsgLevelOverrides(t *testing.T) {
+	overrides, err := parseMsgLevelOverrides("db timeout=error, cache miss = debug,a=b=WARN")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []MsgLevelOverride{
+		{Substring: "db timeout", Level: slog.LevelError},
+		{Substring: "cache miss", Level: slog.LevelDebug},
+		{Substring: "a=b", Level: slog.LevelWarn},
+	}
+	if !reflect.DeepEqual(overrides, expected) {
+		t.Errorf("expected %v, got %v", expected, overrides)
+	}
+
+	for _, s := range []string{"no level", "=error", "db timeout=loud"} {
+		if _, err := parseMsgLevelOverrides(s); !errors.Is(err, ErrInvalidMsgLevelOverride) {
+			t.Errorf("%q: expected ErrInvalidMsgLevelOverride, got %v", s, err)
+		}
+	}
+}
+
+func TestMsgLevelHandler(t *testing.T) {
+	var buf bytes.Buffer
+	overrides := []MsgLevelOverride{
+		{Substring: "db timeout", Level: slog.LevelError},
+		{Substring: "cache miss", Level: slog.LevelDebug},
+	}
+	logger := slog.New(newMsgLevelHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: removeTime}), overrides))
+
+	logger.Info("db timeout after 5s")
+	logger.Warn("cache miss")
+	logger.Info("request served")
+	logger.Debug("db timeout during warmup")
+
+	expected := "level=ERROR msg=\"db timeout after 5s\"\nlevel=INFO msg=\"request served\"\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestLogWithLevelOverride(t *testing.T) {
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	originalConfig := currentConfig.Load()
+	defer currentConfig.Store(originalConfig)
+
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn, ReplaceAttr: removeTime})))
+	currentConfig.Store(&Config{MsgLevelOverrides: []MsgLevelOverride{{Substring: "db timeout", Level: slog.LevelError}}})
+
+	// A matched message is elevated even from below the minimum level
+	LogWithLevelOverride(context.Background(), slog.LevelInfo, "db timeout after 5s", "db", "orders")
+	LogWithLevelOverride(context.Background(), slog.LevelInfo, "request served")
+
+	expected := "level=ERROR msg=\"db timeout after 5s\" db=orders\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestReadConfigMsgLevelOverrides(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerMsgLevelOverrides: "db timeout=error"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []MsgLevelOverride{{Substring: "db timeout", Level: slog.LevelError}}; !reflect.DeepEqual(config.MsgLevelOverrides, expected) {
+		t.Errorf("expected %v, got %v", expected, config.MsgLevelOverrides)
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerMsgLevelOverrides: "db timeout"}); !errors.Is(err, ErrInvalidMsgLevelOverride) {
+		t.Errorf("expected ErrInvalidMsgLevelOverride, got %v", err)
+	}
+}
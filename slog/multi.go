@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+// multiHandler is a handler that fans each record out to several handlers.
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler creates a handler that passes each record to every given
+// handler that is enabled for the record's level. Each handler receives its
+// own clone of the record, so handlers that add attributes to the record or
+// keep it after Handle returns do not affect each other. Errors returned by
+// the handlers are joined.
+func NewMultiHandler(handlers ...slog.Handler) slog.Handler {
+	return &multiHandler{handlers: handlers}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := handler.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+	return &multiHandler{handlers: handlers}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+	return &multiHandler{handlers: handlers}
+}
+
+// describe implements describer. It lists the handler chain of each child,
+// for example "multi(text, json)".
+func (h *multiHandler) describe() string {
+	children := make([]string, len(h.handlers))
+	for i, handler := range h.handlers {
+		children[i] = strings.Join(describeHandler(handler), " > ")
+	}
+	return "multi(" + strings.Join(children, ", ") + ")"
+}
+
+// unwrap implements describer. A multi handler has no single next handler.
+func (h *multiHandler) unwrap() slog.Handler {
+	return nil
+}
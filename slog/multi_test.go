@@ -0,0 +1,122 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHandler keeps the records it handles. If tag is set, it first adds
+// a "tag" attribute to each record to simulate a mutating handler.
+type recordingHandler struct {
+	mu      sync.Mutex
+	level   slog.Level
+	tag     string
+	records []slog.Record
+	err     error
+}
+
+func (h *recordingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	if h.tag != "" {
+		r.AddAttrs(slog.String("tag", h.tag))
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r)
+	return h.err
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler      { return h }
+
+// attrString returns the record's attributes as a comma-separated list.
+func attrString(r slog.Record) string {
+	var attrs []string
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a.String())
+		return true
+	})
+	return strings.Join(attrs, ",")
+}
+
+func TestMultiHandlerClonesRecord(t *testing.T) {
+	first := &recordingHandler{tag: "first"}
+	second := &recordingHandler{tag: "second"}
+	handler := NewMultiHandler(first, second)
+
+	// Spill over the record's inline attribute storage and add the rest one
+	// by one, leaving spare capacity that copies of the record would share.
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+	r.Add("a", 1, "b", 2, "c", 3, "d", 4, "e", 5)
+	r.Add("f", 6)
+	r.Add("g", 7)
+	r.Add("h", 8)
+	if err := handler.Handle(context.Background(), r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, h := range []*recordingHandler{first, second} {
+		if len(h.records) != 1 {
+			t.Fatalf("%s: expected 1 record, got %d", h.tag, len(h.records))
+		}
+		expected := "a=1,b=2,c=3,d=4,e=5,f=6,g=7,h=8,tag=" + h.tag
+		if got := attrString(h.records[0]); got != expected {
+			t.Errorf("%s: expected attributes %s, got %s", h.tag, expected, got)
+		}
+	}
+}
+
+func TestMultiHandlerLevels(t *testing.T) {
+	info := &recordingHandler{level: slog.LevelInfo}
+	errorOnly := &recordingHandler{level: slog.LevelError}
+	handler := NewMultiHandler(info, errorOnly)
+
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("expected debug to be disabled")
+	}
+
+	logger := slog.New(handler)
+	logger.Info("info")
+	logger.Error("error")
+
+	if len(info.records) != 2 || len(errorOnly.records) != 1 {
+		t.Errorf("expected 2 and 1 records, got %d and %d", len(info.records), len(errorOnly.records))
+	}
+}
+
+func TestMultiHandlerErrors(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	handler := NewMultiHandler(&recordingHandler{err: errFirst}, &recordingHandler{err: errSecond})
+
+	err := handler.Handle(context.Background(), slog.Record{Level: slog.LevelInfo})
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("expected both errors to be joined, got %v", err)
+	}
+}
+
+func TestMultiHandlerWithAttrs(t *testing.T) {
+	var jsonBuf, textBuf bytes.Buffer
+	logger := slog.New(NewMultiHandler(
+		slog.NewJSONHandler(&jsonBuf, nil),
+		slog.NewTextHandler(&textBuf, nil),
+	)).With("service", "api").WithGroup("req")
+
+	logger.Info("test", "id", 1)
+
+	if !strings.Contains(jsonBuf.String(), `"service":"api","req":{"id":1}`) {
+		t.Errorf("unexpected json output %q", jsonBuf.String())
+	}
+	if !strings.Contains(textBuf.String(), "service=api req.id=1") {
+		t.Errorf("unexpected text output %q", textBuf.String())
+	}
+}
@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"log/slog"
+	"reflect"
+)
+
+// DefaultNilPlaceholder is the value LOGGER_NIL_ATTR_ACTION=placeholder logs
+// for nil attribute values unless LOGGER_NIL_ATTR_PLACEHOLDER is set.
+const DefaultNilPlaceholder = "<nil>"
+
+// replaceNilAttrs returns a ReplaceAttr function that drops the attributes
+// with a nil value or replaces their value with the config's placeholder
+// string, or nil if nil values are kept as they are.
+//
+// Without it, the handlers render nil values differently: a nil error is
+// "<nil>" in text and null in JSON, while a nil slice is "[]" in text. A value
+// is nil if it is a nil interface, such as a nil error, or a nil pointer, map,
+// slice, channel or function. The built-in attributes are left as they are.
+func replaceNilAttrs(config *Config) func([]string, slog.Attr) slog.Attr {
+	if config.NilAttrAction != "drop" && config.NilAttrAction != "placeholder" {
+		return nil
+	}
+
+	placeholder := config.NilAttrPlaceholder
+	if placeholder == "" {
+		placeholder = DefaultNilPlaceholder
+	}
+	drop := config.NilAttrAction == "drop"
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if (len(groups) == 0 && isBuiltinKey(a.Key)) || !isNilValue(a.Value) {
+			return a
+		}
+		if drop {
+			return slog.Attr{}
+		}
+		return slog.String(a.Key, placeholder)
+	}
+}
+
+// isNilValue reports whether v holds a nil interface or a nil pointer, map,
+// slice, channel or function.
+func isNilValue(v slog.Value) bool {
+	if v.Kind() != slog.KindAny {
+		return false
+	}
+	x := v.Any()
+	if x == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(x); rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
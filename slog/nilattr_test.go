@@ -0,0 +1,62 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestReplaceNilAttrs(t *testing.T) {
+	var nilErr error
+	var nilPtr *bytes.Buffer
+	tests := []struct {
+		action      string
+		placeholder string
+		expected    string
+	}{
+		{action: "", expected: `msg=done err=<nil> buf=<nil> tags=[] user.err=<nil> user.id=1 count=0` + "\n"},
+		{action: "keep", expected: `msg=done err=<nil> buf=<nil> tags=[] user.err=<nil> user.id=1 count=0` + "\n"},
+		{action: "drop", expected: `msg=done user.id=1 count=0` + "\n"},
+		{action: "placeholder", expected: `msg=done err=<nil> buf=<nil> tags=<nil> user.err=<nil> user.id=1 count=0` + "\n"},
+		{action: "placeholder", placeholder: "none", expected: `msg=done err=none buf=none tags=none user.err=none user.id=1 count=0` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action+"/"+tt.placeholder, func(t *testing.T) {
+			config := &Config{HandlerType: "bare", NilAttrAction: tt.action, NilAttrPlaceholder: tt.placeholder}
+			var buf bytes.Buffer
+			logger := slog.New(createHandler(config, &buf))
+
+			logger.Info("done", "err", nilErr, "buf", nilPtr, "tags", []string(nil), slog.Group("user", "err", nilErr, "id", 1), "count", 0)
+			if buf.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestReplaceNilAttrsJSON(t *testing.T) {
+	var nilErr error
+	config := &Config{HandlerType: "json", NilAttrAction: "placeholder"}
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(config, &buf).WithAttrs([]slog.Attr{slog.Any("cause", nilErr)}))
+
+	logger.Info("done", "err", nilErr)
+	if expected := `"cause":"<nil>","err":"<nil>"}` + "\n"; !bytes.HasSuffix(buf.Bytes(), []byte(expected)) {
+		t.Errorf("expected suffix %q, got %q", expected, buf.String())
+	}
+}
+
+func TestReadConfigNilAttrAction(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerNilAttrAction: "Placeholder", EnvLoggerNilAttrPlaceholder: "-"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.NilAttrAction != "placeholder" || config.NilAttrPlaceholder != "-" {
+		t.Errorf("unexpected nil attribute settings: %q %q", config.NilAttrAction, config.NilAttrPlaceholder)
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerNilAttrAction: "hide"}); !errors.Is(err, ErrInvalidNilAttrAction) {
+		t.Errorf("expected ErrInvalidNilAttrAction, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package slog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// optsAliases maps the short LOGGER_OPTS keys to the variables they stand for.
+var optsAliases = map[string]string{
+	"source": EnvLoggerAddSource,
+	"file":   EnvLoggerWriterFilePath,
+}
+
+// optsListVars are the variables whose values are lists that may contain
+// commas themselves.
+var optsListVars = map[string]bool{
+	EnvLoggerOutputs:           true,
+	EnvLoggerAttrAllowlist:     true,
+	EnvLoggerHashKeys:          true,
+	EnvLoggerMaskPatterns:      true,
+	EnvLoggerRenameKeys:        true,
+	EnvLoggerAttrsFromEnv:      true,
+	EnvLoggerMsgLevelOverrides: true,
+}
+
+// parseOpts parses the comma-separated key=value pairs of LOGGER_OPTS into the
+// environment variables they set. A key is the name of a logger variable
+// without the LOGGER_ prefix, in any case ("level", "max_attrs"), or one of
+// optsAliases. The value "false" leaves a variable unset, so that boolean
+// variables, which are enabled by any value, can be turned off.
+//
+// The value of a variable in optsListVars extends up to the next comma that is
+// followed by a key and "=", so that "outputs=json=stdout,text=stderr,level=info"
+// sets LOGGER_OUTPUTS to "json=stdout,text=stderr".
+func parseOpts(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	opts := make(map[string]string)
+	var list string
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		envVar, known := optsEnvVar(key)
+		if list != "" && !(ok && known) {
+			opts[list] += "," + pair
+			continue
+		}
+		list = ""
+
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidOpts, strings.TrimSpace(pair))
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidOpts, strings.ToLower(strings.TrimSpace(key)))
+		}
+		if value = strings.TrimSpace(value); strings.EqualFold(value, "false") {
+			value = ""
+		}
+		opts[envVar] = value
+		if optsListVars[envVar] {
+			list = envVar
+		}
+	}
+	return opts, nil
+}
+
+// optsEnvVar returns the variable that the LOGGER_OPTS key stands for, and
+// whether key is a known key.
+func optsEnvVar(key string) (string, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if envVar, ok := optsAliases[key]; ok {
+		return envVar, true
+	}
+	envVar := "LOGGER_" + strings.ToUpper(key)
+	return envVar, envVar != EnvLoggerOpts && isLoggerEnvVar(envVar)
+}
+
+// optsLookup returns a lookup function that falls back to the settings of
+// LOGGER_OPTS for variables that are not set, so that individually set
+// variables override the combined one. It returns lookup unchanged if
+// LOGGER_OPTS is not set.
+func optsLookup(lookup func(key string) string) (func(key string) string, error) {
+	opts, err := parseOpts(lookup(EnvLoggerOpts))
+	if err != nil || opts == nil {
+		return lookup, err
+	}
+
+	return func(key string) string {
+		if value := lookup(key); value != "" {
+			return value
+		}
+		return opts[key]
+	}, nil
+}
+
+// isLoggerEnvVar reports whether name is one of the logger variables.
+func isLoggerEnvVar(name string) bool {
+	for _, envVar := range loggerEnvVars {
+		if envVar == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package slog
+
+import (
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestReadConfigOpts(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected *Config
+	}{
+		{
+			name:    "combined variable",
+			envVars: map[string]string{EnvLoggerOpts: "level=warn,source=true,handler=json,writer=stderr"},
+			expected: &Config{
+				Level:          slog.LevelWarn,
+				AddSource:      true,
+				HandlerType:    "json",
+				WriterType:     "stderr",
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+		{
+			name:    "full variable names, spaces and case",
+			envVars: map[string]string{EnvLoggerOpts: " Writer = file , FILE=/tmp/app.log, writer_file_no_append=1,"},
+			expected: &Config{
+				Level:              slog.LevelInfo,
+				HandlerType:        "text",
+				WriterType:         "file",
+				WriterFilePath:     "/tmp/app.log",
+				WriterFileNoAppend: true,
+				WriterFilePerm:     DefaultFilePerm,
+			},
+		},
+		{
+			name: "individual variables override the combined one",
+			envVars: map[string]string{
+				EnvLoggerOpts:    "level=debug,source=true,handler=json",
+				EnvLoggerLevel:   "error",
+				EnvLoggerHandler: "text",
+			},
+			expected: &Config{
+				Level:          slog.LevelError,
+				AddSource:      true,
+				HandlerType:    "text",
+				WriterType:     "stderr",
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+		{
+			name:    "false leaves a boolean unset",
+			envVars: map[string]string{EnvLoggerOpts: "source=false,handler=json"},
+			expected: &Config{
+				Level:          slog.LevelInfo,
+				HandlerType:    "json",
+				WriterType:     "stderr",
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+		{
+			name:    "preset from the combined variable",
+			envVars: map[string]string{EnvLoggerOpts: "preset=container,level=debug"},
+			expected: &Config{
+				Level:          slog.LevelDebug,
+				HandlerType:    "json",
+				WriterType:     "stdout",
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := readConfigFromEnv(t, tt.envVars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+
+	for _, opts := range []string{"level", "=info", "colour=true", "opts=level=info", "lvl=debug"} {
+		if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerOpts: opts}); !errors.Is(err, ErrInvalidOpts) {
+			t.Errorf("%q: expected ErrInvalidOpts, got %v", opts, err)
+		}
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerOpts: "level=loud"}); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("expected ErrInvalidLevel, got %v", err)
+	}
+}
+
+func TestParseOptsListValues(t *testing.T) {
+	opts, err := parseOpts(`outputs=json=stdout,text=file:app.log,level=debug,hash_keys=user_email, client_ip,mask_patterns=\d{13,16}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		EnvLoggerOutputs:      "json=stdout,text=file:app.log",
+		EnvLoggerLevel:        "debug",
+		EnvLoggerHashKeys:     "user_email, client_ip",
+		EnvLoggerMaskPatterns: `\d{13,16}`,
+	}
+	if !reflect.DeepEqual(opts, expected) {
+		t.Errorf("expected %v, got %v", expected, opts)
+	}
+
+	// Only list values extend over commas
+	if _, err := parseOpts("level=info,colour=true"); !errors.Is(err, ErrInvalidOpts) {
+		t.Errorf("expected ErrInvalidOpts, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+)
+
+// outputKey is the context key under which WithOutput stores the extra writer.
+type outputKey struct{}
+
+// contextOutput is the context value stored by WithOutput. Its address
+// identifies the writer, since the writer itself may not be comparable.
+type contextOutput struct {
+	w io.Writer
+}
+
+// outputCache is the format handler an outputHandler derived for a context
+// output.
+type outputCache struct {
+	output  *contextOutput
+	handler slog.Handler
+}
+
+// WithOutput returns a copy of ctx that makes context-aware logs made with it
+// also write each record to w, formatted like the logger's own output, for
+// example to send a tenant's logs to a tenant-specific sink. Calling
+// WithOutput again replaces the writer.
+//
+// The record is written to w after the logger's own writer, by the logger
+// that finally handles it, including the attributes and groups of that
+// logger. If ctx is shared between goroutines, w must be safe for concurrent use.
+func WithOutput(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, outputKey{}, &contextOutput{w: w})
+}
+
+// outputHandler is a wrapper handler that also writes each record to the
+// writer stored in the context by WithOutput, using a format handler created
+// from config for that writer. It records WithAttrs and WithGroup calls so it
+// can replay them onto that handler. The handler derived for the most recent
+// context output is cached, so that the records logged with one context reuse
+// it.
+type outputHandler struct {
+	next   slog.Handler
+	config *Config
+	ops    []handlerOp
+	cache  atomic.Pointer[outputCache]
+}
+
+// newOutputHandler creates a new handler that tees records to context outputs.
+func newOutputHandler(next slog.Handler, config *Config) slog.Handler {
+	return &outputHandler{next: next, config: config}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *outputHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *outputHandler) Handle(ctx context.Context, r slog.Record) error {
+	var output *contextOutput
+	if ctx != nil {
+		output, _ = ctx.Value(outputKey{}).(*contextOutput)
+	}
+	if output == nil || output.w == nil {
+		return h.next.Handle(ctx, r)
+	}
+
+	err := h.next.Handle(ctx, r.Clone())
+	return errors.Join(err, h.formatHandler(output).Handle(ctx, r))
+}
+
+// formatHandler returns the format handler for output, with h's operations
+// applied.
+func (h *outputHandler) formatHandler(output *contextOutput) slog.Handler {
+	if c := h.cache.Load(); c != nil && c.output == output {
+		return c.handler
+	}
+	handler := applyHandlerOps(createFormatHandler(h.config, output.w), h.ops)
+	h.cache.Store(&outputCache{output: output, handler: handler})
+	return handler
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *outputHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.derive(h.next.WithAttrs(attrs), handlerOp{attrs: attrs})
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *outputHandler) WithGroup(name string) slog.Handler {
+	return h.derive(h.next.WithGroup(name), handlerOp{group: name})
+}
+
+// describe implements describer.
+func (h *outputHandler) describe() string {
+	return "output"
+}
+
+// unwrap implements describer.
+func (h *outputHandler) unwrap() slog.Handler {
+	return h.next
+}
+
+// derive returns a handler wrapping next with op appended to h's operations.
+func (h *outputHandler) derive(next slog.Handler, op handlerOp) *outputHandler {
+	ops := make([]handlerOp, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &outputHandler{
+		next:   next,
+		config: h.config,
+		ops:    append(ops, op),
+	}
+}
@@ -0,0 +1,76 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithOutput(t *testing.T) {
+	var main, tenant bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "json"}, &main))
+	ctx := WithContext(context.Background(), logger.With("RequestID", "req-1").WithGroup("req"))
+	ctx = WithOutput(ctx, &tenant)
+
+	// The default logger delegates to the context logger, which tees the record
+	defaultLogger := slog.New(createHandler(&Config{HandlerType: "text"}, &bytes.Buffer{}))
+	defaultLogger.InfoContext(ctx, "handled", "path", "/")
+
+	expected := `"msg":"handled","RequestID":"req-1","req":{"path":"/"}}`
+	if !strings.Contains(main.String(), expected) {
+		t.Errorf("expected main output to contain %s, got %q", expected, main.String())
+	}
+	if !strings.Contains(tenant.String(), expected) {
+		t.Errorf("expected tenant output to contain %s, got %q", expected, tenant.String())
+	}
+	if strings.Count(tenant.String(), "\n") != 1 {
+		t.Errorf("expected the record to be written to the tenant output once, got %q", tenant.String())
+	}
+
+	// Logs without the output context are not teed
+	tenant.Reset()
+	logger.Info("other")
+	if tenant.Len() != 0 {
+		t.Errorf("expected no tenant output, got %q", tenant.String())
+	}
+}
+
+func TestWithOutputFormat(t *testing.T) {
+	var main, tenant bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare", MaxAttrs: 1}, &main))
+	ctx := WithOutput(context.Background(), &tenant)
+
+	logger.InfoContext(ctx, "handled", "a", 1, "b", 2)
+
+	expected := "msg=handled a=1 _overflow=1\n"
+	if main.String() != expected || tenant.String() != expected {
+		t.Errorf("expected both outputs to be %q, got %q and %q", expected, main.String(), tenant.String())
+	}
+}
+
+func TestWithOutputCache(t *testing.T) {
+	var main, tenant, other bytes.Buffer
+	h := newOutputHandler(slog.NewTextHandler(&main, nil), &Config{HandlerType: "text"}).(*outputHandler)
+	logger := slog.New(h)
+	ctx := WithOutput(context.Background(), &tenant)
+
+	// Records logged with the same context reuse the derived handler
+	logger.InfoContext(ctx, "first")
+	cached := h.cache.Load()
+	logger.InfoContext(ctx, "second")
+	if h.cache.Load() != cached {
+		t.Errorf("expected the output handler to be reused")
+	}
+	if strings.Count(tenant.String(), "\n") != 2 {
+		t.Errorf("expected two records in the tenant output, got %q", tenant.String())
+	}
+
+	// Another output replaces the cached handler
+	otherCtx := WithOutput(context.Background(), &other)
+	logger.InfoContext(otherCtx, "third")
+	if h.cache.Load() == cached || !strings.Contains(other.String(), "msg=third") {
+		t.Errorf("expected a new output handler for the other writer, got %q", other.String())
+	}
+}
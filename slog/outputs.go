@@ -0,0 +1,87 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Output describes one handler/writer pair of LOGGER_OUTPUTS.
+type Output struct {
+	// HandlerType is the type of handler to use.
+	HandlerType string
+	// WriterType is the type of writer to use.
+	WriterType string
+	// WriterFilePath is the path to the log file if WriterType is "file".
+	WriterFilePath string
+}
+
+// parseOutputs parses a comma-separated list of outputs, each written as
+// handler=writer for stdout and stderr, or handler=file:path for files.
+func parseOutputs(s string) ([]Output, error) {
+	var outputs []Output
+	for _, entry := range splitList(s) {
+		handlerType, writer, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q: want handler=writer", ErrInvalidOutputs, entry)
+		}
+		writerType, path, hasPath := strings.Cut(writer, ":")
+
+		output := Output{
+			HandlerType:    strings.ToLower(strings.TrimSpace(handlerType)),
+			WriterType:     strings.ToLower(strings.TrimSpace(writerType)),
+			WriterFilePath: path,
+		}
+		if !isValidHandlerType(output.HandlerType) {
+			return nil, fmt.Errorf("%w: %q: %w: %v", ErrInvalidOutputs, entry, ErrInvalidHandlerType, output.HandlerType)
+		}
+		if !isValidWriterType(output.WriterType) {
+			return nil, fmt.Errorf("%w: %q: %w: %v", ErrInvalidOutputs, entry, ErrInvalidWriterType, output.WriterType)
+		}
+		if output.WriterType == "file" {
+			if path == "" {
+				return nil, fmt.Errorf("%w: %q: %w", ErrInvalidOutputs, entry, ErrMissingFilePath)
+			}
+		} else if hasPath {
+			return nil, fmt.Errorf("%w: %q: a path is only allowed for file writers", ErrInvalidOutputs, entry)
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
+
+// hasFileOutput reports whether any of the outputs writes to a file.
+func hasFileOutput(outputs []Output) bool {
+	for _, output := range outputs {
+		if output.WriterType == "file" {
+			return true
+		}
+	}
+	return false
+}
+
+// buildOutputsHandler creates a handler that writes every record to each of
+// the config's outputs. The outputs share all settings other than their
+// handler type and writer; the handler wrappers are applied once around them,
+// so every output receives the same records.
+func buildOutputsHandler(config *Config) (slog.Handler, error) {
+	handlers := make([]slog.Handler, 0, len(config.Outputs))
+	for _, output := range config.Outputs {
+		c := *config
+		c.HandlerType = output.HandlerType
+		c.WriterType = output.WriterType
+		c.WriterFilePath = output.WriterFilePath
+		c.Outputs = nil
+
+		writer, err := buildWriter(&c)
+		if err != nil {
+			return nil, err
+		}
+		handlers = append(handlers, createFormatHandler(&c, writer))
+	}
+
+	// Context outputs use the format of the first output.
+	c := *config
+	c.HandlerType = config.Outputs[0].HandlerType
+	return wrapHandler(&c, NewMultiHandler(handlers...)), nil
+}
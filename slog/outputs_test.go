@@ -0,0 +1,109 @@
+package slog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOutputs(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "app.log")
+	jsonPath := filepath.Join(dir, "app.json")
+	clearEnvVars()
+	os.Setenv(EnvLoggerLevel, "warn")
+	os.Setenv(EnvLoggerOutputs, "text=file:"+textPath+", json=file:"+jsonPath)
+
+	logger, err := Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("hidden")
+	logger.Warn("first", "n", 1)
+	logger.With("service", "api").Error("second", "n", 2)
+
+	if err := Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	textData, err := os.ReadFile(textPath)
+	if err != nil {
+		t.Fatalf("failed to read text output: %v", err)
+	}
+	jsonData, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("failed to read json output: %v", err)
+	}
+
+	textLines := strings.Split(strings.TrimSpace(string(textData)), "\n")
+	jsonLines := strings.Split(strings.TrimSpace(string(jsonData)), "\n")
+	if len(textLines) != 2 || len(jsonLines) != 2 {
+		t.Fatalf("expected 2 records in each output, got %q and %q", textData, jsonData)
+	}
+	if !strings.Contains(textLines[0], "msg=first n=1") || !strings.Contains(textLines[1], "msg=second service=api n=2") {
+		t.Errorf("unexpected text output %q", textData)
+	}
+	for i, msg := range []string{"first", "second"} {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(jsonLines[i]), &record); err != nil {
+			t.Fatalf("failed to parse json output %q: %v", jsonLines[i], err)
+		}
+		if record["msg"] != msg || record["n"] != float64(i+1) {
+			t.Errorf("expected json record %q, got %v", msg, record)
+		}
+	}
+}
+
+func TestParseOutputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Output
+		wantErr  error
+	}{
+		{name: "empty", input: ""},
+		{
+			name:  "pairs",
+			input: "TEXT=stderr, json=file:/var/log/app-%Y.json",
+			expected: []Output{
+				{HandlerType: "text", WriterType: "stderr"},
+				{HandlerType: "json", WriterType: "file", WriterFilePath: "/var/log/app-%Y.json"},
+			},
+		},
+		{name: "path with colon", input: "json=file:C:/logs/app.json", expected: []Output{
+			{HandlerType: "json", WriterType: "file", WriterFilePath: "C:/logs/app.json"},
+		}},
+		{name: "missing writer", input: "json", wantErr: ErrInvalidOutputs},
+		{name: "invalid handler", input: "xml=stdout", wantErr: ErrInvalidHandlerType},
+		{name: "invalid writer", input: "json=syslog", wantErr: ErrInvalidWriterType},
+		{name: "missing path", input: "json=file", wantErr: ErrMissingFilePath},
+		{name: "path for stdout", input: "json=stdout:app.log", wantErr: ErrInvalidOutputs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputs, err := parseOutputs(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, ErrInvalidOutputs) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(outputs, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, outputs)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package slog
+
+import (
+	"sync"
+	"time"
+)
+
+// periodicTask is a background goroutine that calls a function at a fixed
+// interval until it is stopped.
+type periodicTask struct {
+	ticker  *time.Ticker
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// startPeriodic starts a goroutine that calls fn every interval. If
+// immediately is set, fn is also called once right away.
+func startPeriodic(interval time.Duration, immediately bool, fn func()) *periodicTask {
+	t := &periodicTask{
+		ticker:  time.NewTicker(interval),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go func() {
+		defer close(t.stopped)
+		if immediately {
+			fn()
+		}
+		for {
+			select {
+			case <-t.ticker.C:
+				fn()
+			case <-t.done:
+				return
+			}
+		}
+	}()
+	return t
+}
+
+// stop stops the task and waits for its goroutine to exit.
+// It is safe to call more than once.
+func (t *periodicTask) stop() {
+	t.once.Do(func() {
+		t.ticker.Stop()
+		close(t.done)
+		<-t.stopped
+	})
+}
@@ -0,0 +1,36 @@
+package slog
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// setupPIDFile writes the PID file configured by LOGGER_PIDFILE, if any, and
+// registers its removal with Close. A failure to write the file does not fail
+// the build; it is logged as a warning through logger instead.
+func setupPIDFile(config *Config, logger *slog.Logger) {
+	if config.PIDFile == "" {
+		return
+	}
+	if err := writePIDFile(config.PIDFile); err != nil {
+		logger.Warn("failed to write pid file", "path", config.PIDFile, "error", err)
+	}
+}
+
+// writePIDFile writes the current process id to path and registers the
+// removal of the file with Close.
+func writePIDFile(path string) error {
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		return err
+	}
+	registerCloser(closeMarkers, func() error {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	})
+	return nil
+}
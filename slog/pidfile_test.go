@@ -0,0 +1,68 @@
+package slog
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPIDFile(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	path := filepath.Join(t.TempDir(), "app.pid")
+	clearEnvVars()
+	os.Setenv(EnvLoggerWriter, "stderr")
+	os.Setenv(EnvLoggerPIDFile, path)
+
+	if _, err := Build(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read pid file: %v", err)
+	}
+	if expected := strconv.Itoa(os.Getpid()) + "\n"; string(data) != expected {
+		t.Errorf("expected pid file content %q, got %q", expected, data)
+	}
+
+	if err := Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected pid file to be removed on Close, got %v", err)
+	}
+}
+
+func TestPIDFileWriteError(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "test.log")
+	clearEnvVars()
+	os.Setenv(EnvLoggerWriter, "file")
+	os.Setenv(EnvLoggerWriterFilePath, logPath)
+	os.Setenv(EnvLoggerPIDFile, filepath.Join(dir, "missing", "app.pid"))
+
+	if _, err := Build(); err != nil {
+		t.Fatalf("expected Build to succeed despite the pid file error, got %v", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "failed to write pid file") {
+		t.Errorf("expected a warning in the log, got %q", data)
+	}
+}
@@ -0,0 +1,108 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// DefaultBrokenPipeAction is what happens to records written after the log
+// destination turned out to be a broken pipe when no action is configured.
+const DefaultBrokenPipeAction = "stderr"
+
+// exitFunc exits the process for the "exit" broken pipe action.
+var exitFunc = os.Exit
+
+// brokenPipeWriter is a writer that handles the failure of a writer whose
+// destination is a pipe without a reader (EPIPE). After the first such
+// failure the writer is considered broken and is no longer attempted:
+//
+//	ignore  records are discarded
+//	stderr  records are written to the fallback writer, after a notice;
+//	        they are discarded if there is no fallback
+//	exit    the process exits with status 1
+//
+// Other write errors are returned as they are.
+type brokenPipeWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	fallback io.Writer
+	action   string
+	broken   bool
+}
+
+// newBrokenPipeWriter creates a new writer that handles broken pipes of w with action.
+func newBrokenPipeWriter(w, fallback io.Writer, action string) *brokenPipeWriter {
+	if action == "" {
+		action = DefaultBrokenPipeAction
+	}
+	return &brokenPipeWriter{w: w, fallback: fallback, action: action}
+}
+
+// Write implements io.Writer.
+func (b *brokenPipeWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.broken {
+		n, err := b.w.Write(p)
+		if !errors.Is(err, syscall.EPIPE) {
+			return n, err
+		}
+		b.broken = true
+		switch b.action {
+		case "exit":
+			exitFunc(1)
+		case "stderr":
+			if b.fallback != nil {
+				fmt.Fprintf(b.fallback, "log writer: %v; writing logs to stderr\n", err)
+			}
+		}
+	}
+
+	if b.action == "stderr" && b.fallback != nil {
+		return b.fallback.Write(p)
+	}
+	return len(p), nil
+}
+
+var (
+	sigpipeMu sync.Mutex
+	sigpipeCh chan os.Signal
+)
+
+// ignoreSIGPIPE stops writes to a broken pipe on stdout or stderr from
+// killing the process with SIGPIPE, so that they fail with EPIPE instead.
+// Go only raises SIGPIPE for these two file descriptors; writes to other
+// pipes always fail with EPIPE.
+//
+// The signal is received on a drained channel rather than ignored, so that
+// the disposition is not inherited by child processes across exec. Close
+// stops the delivery, restoring the default behavior.
+func ignoreSIGPIPE() {
+	sigpipeMu.Lock()
+	defer sigpipeMu.Unlock()
+	if sigpipeCh != nil {
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGPIPE)
+	go func() {
+		for range ch {
+		}
+	}()
+	sigpipeCh = ch
+	registerCloser(closeStopTasks, func() error {
+		sigpipeMu.Lock()
+		defer sigpipeMu.Unlock()
+		signal.Stop(ch)
+		close(ch)
+		sigpipeCh = nil
+		return nil
+	})
+}
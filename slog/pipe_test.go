@@ -0,0 +1,142 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// brokenPipe returns the write end of a pipe whose reader has been closed.
+func brokenPipe(t *testing.T) *os.File {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	r.Close()
+	t.Cleanup(func() { w.Close() })
+	return w
+}
+
+func TestBrokenPipeWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   string
+		fallback bool
+		expected string
+	}{
+		{name: "Ignore", action: "ignore", fallback: true, expected: ""},
+		{name: "Stderr", action: "stderr", fallback: true, expected: "first\nsecond\n"},
+		{name: "Default", action: "", fallback: true, expected: "first\nsecond\n"},
+		{name: "Stderr Without Fallback", action: "stderr", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fallback bytes.Buffer
+			var w *brokenPipeWriter
+			if tt.fallback {
+				w = newBrokenPipeWriter(brokenPipe(t), &fallback, tt.action)
+			} else {
+				w = newBrokenPipeWriter(brokenPipe(t), nil, tt.action)
+			}
+
+			for _, line := range []string{"first\n", "second\n"} {
+				if n, err := w.Write([]byte(line)); err != nil || n != len(line) {
+					t.Fatalf("expected the broken pipe to be handled, got %d, %v", n, err)
+				}
+			}
+
+			got := fallback.String()
+			if tt.expected != "" {
+				notice, rest, _ := strings.Cut(got, "\n")
+				if !strings.Contains(notice, "broken pipe") {
+					t.Errorf("expected a broken pipe notice, got %q", notice)
+				}
+				got = rest
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q on the fallback, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBrokenPipeWriterExit(t *testing.T) {
+	defer func(fn func(int)) { exitFunc = fn }(exitFunc)
+	code := -1
+	exitFunc = func(c int) { code = c }
+
+	w := newBrokenPipeWriter(brokenPipe(t), io.Discard, "exit")
+	w.Write([]byte("record\n"))
+	if code != 1 {
+		t.Errorf("expected exit status 1, got %d", code)
+	}
+}
+
+func TestBrokenPipeWriterOtherErrors(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	r.Close()
+	w.Close()
+
+	var fallback bytes.Buffer
+	bw := newBrokenPipeWriter(w, &fallback, "stderr")
+	if _, err := bw.Write([]byte("record\n")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected other errors to be returned, got %v", err)
+	}
+	if fallback.Len() != 0 {
+		t.Errorf("expected nothing on the fallback, got %q", fallback.String())
+	}
+}
+
+func TestReadConfigBrokenPipeAction(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerBrokenPipeAction: "Exit"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.BrokenPipeAction != "exit" {
+		t.Errorf("expected the exit action, got %q", config.BrokenPipeAction)
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerBrokenPipeAction: "retry"}); !errors.Is(err, ErrInvalidBrokenPipeAction) {
+		t.Errorf("expected ErrInvalidBrokenPipeAction, got %v", err)
+	}
+}
+
+func TestWrapWriterSIGPIPE(t *testing.T) {
+	defer Close()
+
+	// By default SIGPIPE is left alone
+	wrapWriter(&Config{}, os.Stdout)
+	wrapWriter(&Config{BrokenPipeAction: "exit"}, os.Stdout)
+	sigpipeMu.Lock()
+	handled := sigpipeCh != nil
+	sigpipeMu.Unlock()
+	if handled {
+		t.Fatal("expected SIGPIPE not to be handled without an explicit action")
+	}
+
+	wrapWriter(&Config{BrokenPipeAction: "ignore"}, os.Stderr)
+	sigpipeMu.Lock()
+	handled = sigpipeCh != nil
+	sigpipeMu.Unlock()
+	if !handled {
+		t.Fatal("expected SIGPIPE to be handled with an explicit action")
+	}
+
+	if err := Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sigpipeMu.Lock()
+	handled = sigpipeCh != nil
+	sigpipeMu.Unlock()
+	if handled {
+		t.Error("expected Close to stop handling SIGPIPE")
+	}
+}
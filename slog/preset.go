@@ -0,0 +1,46 @@
+package slog
+
+import (
+	"fmt"
+	"strings"
+)
+
+// presets maps each LOGGER_PRESET value to the environment variables it sets.
+var presets = map[string]map[string]string{
+	// container logs json to stdout, where container runtimes collect it.
+	"container": {
+		EnvLoggerLevel:   "info",
+		EnvLoggerHandler: "json",
+		EnvLoggerWriter:  "stdout",
+	},
+	// debug-file logs everything as text with source positions to a local file.
+	"debug-file": {
+		EnvLoggerLevel:          "debug",
+		EnvLoggerAddSource:      "true",
+		EnvLoggerHandler:        "text",
+		EnvLoggerWriter:         "file",
+		EnvLoggerWriterFilePath: "debug.log",
+	},
+}
+
+// presetLookup returns a lookup function that falls back to the values of the
+// preset selected by LOGGER_PRESET for variables that are not set, so that
+// explicitly set variables override the preset. It returns lookup unchanged
+// if no preset is selected.
+func presetLookup(lookup func(key string) string) (func(key string) string, error) {
+	name := strings.ToLower(lookup(EnvLoggerPreset))
+	if name == "" {
+		return lookup, nil
+	}
+	preset, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPreset, name)
+	}
+
+	return func(key string) string {
+		if value := lookup(key); value != "" {
+			return value
+		}
+		return preset[key]
+	}, nil
+}
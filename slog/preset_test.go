@@ -0,0 +1,75 @@
+package slog
+
+import (
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestReadConfigPreset(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	tests := []struct {
+		name     string
+		envVars  map[string]string
+		expected *Config
+	}{
+		{
+			name:    "container",
+			envVars: map[string]string{EnvLoggerPreset: "container"},
+			expected: &Config{
+				Level:          slog.LevelInfo,
+				HandlerType:    "json",
+				WriterType:     "stdout",
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+		{
+			name:    "debug-file",
+			envVars: map[string]string{EnvLoggerPreset: "Debug-File"},
+			expected: &Config{
+				Level:          slog.LevelDebug,
+				AddSource:      true,
+				HandlerType:    "text",
+				WriterType:     "file",
+				WriterFilePath: "debug.log",
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+		{
+			name: "explicit variables override the preset",
+			envVars: map[string]string{
+				EnvLoggerPreset:         "debug-file",
+				EnvLoggerLevel:          "warn",
+				EnvLoggerWriterFilePath: "/tmp/app.log",
+			},
+			expected: &Config{
+				Level:          slog.LevelWarn,
+				AddSource:      true,
+				HandlerType:    "text",
+				WriterType:     "file",
+				WriterFilePath: "/tmp/app.log",
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := readConfigFromEnv(t, tt.envVars)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(config, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, config)
+			}
+		})
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerPreset: "unknown"}); !errors.Is(err, ErrInvalidPreset) {
+		t.Errorf("expected ErrInvalidPreset, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// Debugf logs a message formatted with fmt.Sprintf at debug level through the
+// logger stored in ctx (see FromContext).
+//
+// The Printf-style helpers ease the migration from fmt-based logging; prefer
+// passing structured attributes to the slog functions in new code. The message
+// is only formatted if the level is enabled.
+func Debugf(ctx context.Context, format string, args ...any) {
+	logf(ctx, slog.LevelDebug, format, args...)
+}
+
+// Infof logs a message formatted with fmt.Sprintf at info level through the
+// logger stored in ctx. See Debugf.
+func Infof(ctx context.Context, format string, args ...any) {
+	logf(ctx, slog.LevelInfo, format, args...)
+}
+
+// Warnf logs a message formatted with fmt.Sprintf at warn level through the
+// logger stored in ctx. See Debugf.
+func Warnf(ctx context.Context, format string, args ...any) {
+	logf(ctx, slog.LevelWarn, format, args...)
+}
+
+// Errorf logs a message formatted with fmt.Sprintf at error level through the
+// logger stored in ctx. See Debugf. Unlike fmt.Errorf, it does not return an
+// error.
+func Errorf(ctx context.Context, format string, args ...any) {
+	logf(ctx, slog.LevelError, format, args...)
+}
+
+// logf logs a formatted message at level through the logger stored in ctx,
+// reporting the caller of the exported helper as the source.
+func logf(ctx context.Context, level slog.Level, format string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	handler := FromContext(ctx).Handler()
+	if !handler.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip [runtime.Callers, logf, Infof]
+	r := slog.NewRecord(time.Now(), level, fmt.Sprintf(format, args...), pcs[0])
+	_ = handler.Handle(ctx, r)
+}
@@ -0,0 +1,67 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// countingStringer counts how often it is formatted.
+type countingStringer struct {
+	calls *int
+}
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "value"
+}
+
+func TestPrintfHelpers(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug, AddSource: true}))
+	ctx := WithContext(context.Background(), logger)
+
+	Debugf(ctx, "debug %d", 1)
+	Infof(ctx, "info %s", "two")
+	Warnf(ctx, "warn %v", 3.5)
+	Errorf(ctx, "error %q", "four")
+
+	out := buf.String()
+	for _, expected := range []string{
+		`level=DEBUG source=`,
+		`msg="debug 1"`,
+		`level=INFO source=`,
+		`msg="info two"`,
+		`level=WARN source=`,
+		`msg="warn 3.5"`,
+		`level=ERROR source=`,
+		`msg="error \"four\""`,
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected %q in %q", expected, out)
+		}
+	}
+	if strings.Count(out, "printf_test.go:") != 4 {
+		t.Errorf("expected the caller as the source, got %q", out)
+	}
+}
+
+func TestPrintfHelpersDisabledLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	ctx := WithContext(context.Background(), logger)
+
+	var calls int
+	Debugf(ctx, "debug %v", countingStringer{&calls})
+	Infof(ctx, "info %v", countingStringer{&calls})
+	if calls != 0 || buf.Len() != 0 {
+		t.Errorf("expected disabled levels to skip formatting, got %d calls and %q", calls, buf.String())
+	}
+
+	Warnf(ctx, "warn %v", countingStringer{&calls})
+	if calls != 1 || !strings.Contains(buf.String(), `msg="warn value"`) {
+		t.Errorf("expected one formatted record, got %d calls and %q", calls, buf.String())
+	}
+}
@@ -0,0 +1,29 @@
+package slog
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// probeWriter performs a test write to a file writer so that write errors
+// surface when the logger is built instead of on the first record.
+//
+// The probe writes no data, so that the file is left unchanged and framed
+// output such as LOGGER_FRAMING=length is not corrupted. It reports files
+// that fail every write, such as devices like /dev/full or files that are not
+// open for writing; a disk that is merely full is only noticed when a record
+// is written.
+func probeWriter(config *Config, w io.Writer) error {
+	if config.WriterType != "file" {
+		return nil
+	}
+	file, ok := w.(*os.File)
+	if !ok {
+		return nil
+	}
+	if _, err := file.Write(nil); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrWriterProbe, file.Name(), err)
+	}
+	return nil
+}
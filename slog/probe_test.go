@@ -0,0 +1,134 @@
+package slog
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProbeWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		noAppend bool
+		expected string
+	}{
+		{name: "Append Mode", noAppend: false, expected: "existing\nrecord\n"},
+		{name: "Truncate Mode", noAppend: true, expected: "record\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "probe.log")
+			if err := os.WriteFile(path, []byte("existing\n"), 0644); err != nil {
+				t.Fatalf("failed to prepare log file: %v", err)
+			}
+
+			config := &Config{
+				WriterType:         "file",
+				WriterFilePath:     path,
+				WriterFileNoAppend: tt.noAppend,
+				WriterFilePerm:     0644,
+			}
+			writer, err := createWriter(config)
+			if err != nil {
+				t.Fatalf("unexpected error creating writer: %v", err)
+			}
+			file := writer.(*os.File)
+			defer file.Close()
+
+			if err := probeWriter(config, writer); err != nil {
+				t.Fatalf("unexpected probe error: %v", err)
+			}
+			if _, err := file.Write([]byte("record\n")); err != nil {
+				t.Fatalf("unexpected write error: %v", err)
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("expected content %q, got %q", tt.expected, content)
+			}
+		})
+	}
+}
+
+func TestBuildWithWriterProbe(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	// /dev/full accepts open but fails every write, like a read-only
+	// disk. A read-only directory cannot be used here because tests may run
+	// as root, which bypasses permission checks.
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full is not available")
+	}
+
+	clearEnvVars()
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, "/dev/full")
+
+	// Without the probe the failure goes unnoticed at build time
+	logger, err := Build()
+	if err != nil || logger == nil {
+		t.Fatalf("expected build without probe to succeed, got %v", err)
+	}
+
+	t.Setenv(EnvLoggerWriterProbe, "true")
+	logger, err = Build()
+	if !errors.Is(err, ErrWriterProbe) {
+		t.Errorf("expected ErrWriterProbe, got %v", err)
+	}
+	if logger != nil {
+		t.Errorf("expected nil logger when probe fails")
+	}
+}
+
+func TestBuildWithWriterProbeFraming(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	// A length-prefixed stream that the logger appends to
+	path := filepath.Join(t.TempDir(), "framed.log")
+	existing := []byte("existing")
+	if err := os.WriteFile(path, append(binary.BigEndian.AppendUint32(nil, uint32(len(existing))), existing...), 0644); err != nil {
+		t.Fatalf("failed to prepare log file: %v", err)
+	}
+
+	clearEnvVars()
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, path)
+	t.Setenv(EnvLoggerFraming, "length")
+	t.Setenv(EnvLoggerWriterProbe, "true")
+	logger, err := Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("record")
+	Close()
+
+	// The probe must not leave bytes between the frames
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	var frames []string
+	for len(content) > 0 {
+		if len(content) < 4 || len(content)-4 < int(binary.BigEndian.Uint32(content)) {
+			t.Fatalf("corrupt frame at %q", content)
+		}
+		n := int(binary.BigEndian.Uint32(content))
+		frames = append(frames, string(content[4:4+n]))
+		content = content[4+n:]
+	}
+	if len(frames) != 2 || frames[0] != "existing" || !strings.Contains(frames[1], "msg=record") {
+		t.Errorf("expected the existing frame and the record, got %q", frames)
+	}
+}
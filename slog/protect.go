@@ -0,0 +1,91 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ProtectedKeyPrefix is the prefix LOGGER_PROTECT_BUILTINS adds to the keys of
+// user attributes that collide with a built-in attribute.
+const ProtectedKeyPrefix = "fields."
+
+// protectHandler is a wrapper handler that renames user attributes whose key
+// is that of a built-in attribute ("time", "level", "msg" or "source") by
+// prefixing it with ProtectedKeyPrefix, so that they cannot be mistaken for
+// or overwrite the real fields. Only top-level attributes can collide;
+// attributes inside groups are left as they are.
+type protectHandler struct {
+	next    slog.Handler
+	grouped bool
+}
+
+// newProtectHandler creates a new handler that renames user attributes
+// colliding with built-in keys.
+func newProtectHandler(next slog.Handler) slog.Handler {
+	return &protectHandler{next: next}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *protectHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *protectHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.grouped || !hasBuiltinKey(r) {
+		return h.next.Handle(ctx, r)
+	}
+
+	protected := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		protected.AddAttrs(protectAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, protected)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *protectHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if !h.grouped {
+		protected := make([]slog.Attr, len(attrs))
+		for i, a := range attrs {
+			protected[i] = protectAttr(a)
+		}
+		attrs = protected
+	}
+	return &protectHandler{next: h.next.WithAttrs(attrs), grouped: h.grouped}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *protectHandler) WithGroup(name string) slog.Handler {
+	return &protectHandler{next: h.next.WithGroup(name), grouped: true}
+}
+
+// describe implements describer.
+func (h *protectHandler) describe() string {
+	return "protect"
+}
+
+// unwrap implements describer.
+func (h *protectHandler) unwrap() slog.Handler {
+	return h.next
+}
+
+// hasBuiltinKey reports whether any attribute of r has a built-in key.
+func hasBuiltinKey(r slog.Record) bool {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = isBuiltinKey(a.Key)
+		return !found
+	})
+	return found
+}
+
+// protectAttr returns a with ProtectedKeyPrefix added to its key if the key
+// is that of a built-in attribute.
+func protectAttr(a slog.Attr) slog.Attr {
+	if isBuiltinKey(a.Key) {
+		a.Key = ProtectedKeyPrefix + a.Key
+	}
+	return a
+}
@@ -0,0 +1,50 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestProtectHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "json", ProtectBuiltins: true}, &buf))
+
+	logger.With("msg", "context").Warn("real message", "level", "fake", "time", "yesterday", "k", "v")
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if got["level"] != "WARN" || got["msg"] != "real message" {
+		t.Errorf("expected the built-in fields to be kept, got %v", got)
+	}
+	for key, expected := range map[string]string{"fields.level": "fake", "fields.time": "yesterday", "fields.msg": "context", "k": "v"} {
+		if got[key] != expected {
+			t.Errorf("%s: expected %q, got %v", key, expected, got[key])
+		}
+	}
+}
+
+func TestProtectHandlerGroups(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare", ProtectBuiltins: true}, &buf))
+
+	// Attributes inside groups cannot collide with built-ins
+	logger.WithGroup("req").With("time", "1ms").Info("test", "level", 2)
+	if expected := "msg=test req.time=1ms req.level=2"; strings.TrimSuffix(buf.String(), "\n") != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestReadConfigProtectBuiltins(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerProtectBuiltins: "true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.ProtectBuiltins {
+		t.Errorf("expected ProtectBuiltins to be enabled")
+	}
+}
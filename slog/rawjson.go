@@ -0,0 +1,30 @@
+package slog
+
+import (
+	"encoding/json"
+	"log/slog"
+)
+
+// RawJSON returns an attribute whose value is embedded verbatim by the json
+// handler, so pre-formatted JSON appears as a nested value rather than a
+// quoted string. If data is not valid JSON, the attribute falls back to a
+// plain string value.
+func RawJSON(key string, data json.RawMessage) slog.Attr {
+	if !json.Valid(data) {
+		return slog.String(key, string(data))
+	}
+	return slog.Any(key, data)
+}
+
+// replaceRawJSON is a ReplaceAttr function that downgrades json.RawMessage
+// values holding invalid JSON to plain strings. Without it the json handler
+// would fail to encode such attributes.
+func replaceRawJSON(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+	if raw, ok := a.Value.Any().(json.RawMessage); ok && !json.Valid(raw) {
+		return slog.String(a.Key, string(raw))
+	}
+	return a
+}
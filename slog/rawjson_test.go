@@ -0,0 +1,62 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestRawJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     slog.Attr
+		expected any
+	}{
+		{
+			name:     "Valid Object",
+			attr:     RawJSON("payload", json.RawMessage(`{"id": 1, "tags": ["a", "b"]}`)),
+			expected: map[string]any{"id": float64(1), "tags": []any{"a", "b"}},
+		},
+		{
+			name:     "Valid Array",
+			attr:     RawJSON("payload", json.RawMessage(`[1,2,3]`)),
+			expected: []any{float64(1), float64(2), float64(3)},
+		},
+		{
+			name:     "Invalid Payload",
+			attr:     RawJSON("payload", json.RawMessage(`{"id": 1`)),
+			expected: `{"id": 1`,
+		},
+		{
+			name:     "Invalid Payload Without Helper",
+			attr:     slog.Any("payload", json.RawMessage(`not json`)),
+			expected: "not json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(createHandler(&Config{HandlerType: "json"}, &buf))
+			logger.Info("test message", tt.attr)
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+			}
+
+			got, err := json.Marshal(record["payload"])
+			if err != nil {
+				t.Fatalf("failed to marshal payload: %v", err)
+			}
+			want, err := json.Marshal(tt.expected)
+			if err != nil {
+				t.Fatalf("failed to marshal expected value: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("payload: expected %s, got %s", want, got)
+			}
+		})
+	}
+}
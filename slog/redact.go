@@ -0,0 +1,132 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// redaction returns a ReplaceAttr function that applies the config's hash
+// keys and mask patterns, or nil if neither is configured.
+func redaction(config *Config) func([]string, slog.Attr) slog.Attr {
+	var fns []func([]string, slog.Attr) slog.Attr
+	for _, fn := range []func([]string, slog.Attr) slog.Attr{
+		replaceHashKeys(config),
+		replaceMaskPatterns(config),
+	} {
+		if fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+	if len(fns) == 0 {
+		return nil
+	}
+	return chainReplaceAttr(fns...)
+}
+
+// replaceRedaction returns the redaction ReplaceAttr function for the output
+// handlers, or nil if there is none or it depends on the record level, in
+// which case the redactLevelHandler applies it instead.
+func replaceRedaction(config *Config) func([]string, slog.Attr) slog.Attr {
+	if config.RedactLevel != nil {
+		return nil
+	}
+	return redaction(config)
+}
+
+// redactLevelHandler is a wrapper handler that applies the hash keys and mask
+// patterns only to records at or above level. A ReplaceAttr function does not
+// see the level of the record, so the redaction is done here instead.
+//
+// Attributes added with WithAttrs are kept both as they are and redacted, in
+// two derived handlers, and each record is passed to the one matching its level.
+type redactLevelHandler struct {
+	next     slog.Handler
+	redacted slog.Handler
+	level    slog.Leveler
+	replace  func([]string, slog.Attr) slog.Attr
+	groups   []string
+}
+
+// newRedactLevelHandler creates a new handler that redacts records at or
+// above level with replace.
+func newRedactLevelHandler(next slog.Handler, level slog.Leveler, replace func([]string, slog.Attr) slog.Attr) slog.Handler {
+	return &redactLevelHandler{next: next, redacted: next, level: level, replace: replace}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *redactLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *redactLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level.Level() {
+		return h.next.Handle(ctx, r)
+	}
+
+	msg := r.Message
+	if a := h.replace(nil, slog.String(slog.MessageKey, msg)); a.Value.Kind() == slog.KindString {
+		msg = a.Value.String()
+	}
+	redacted := slog.NewRecord(r.Time, r.Level, msg, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(h.groups, a))
+		return true
+	})
+	return h.redacted.Handle(ctx, redacted)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *redactLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(h.groups, a)
+	}
+	return &redactLevelHandler{
+		next:     h.next.WithAttrs(attrs),
+		redacted: h.redacted.WithAttrs(redacted),
+		level:    h.level,
+		replace:  h.replace,
+		groups:   h.groups,
+	}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *redactLevelHandler) WithGroup(name string) slog.Handler {
+	return &redactLevelHandler{
+		next:     h.next.WithGroup(name),
+		redacted: h.redacted.WithGroup(name),
+		level:    h.level,
+		replace:  h.replace,
+		groups:   append(slices.Clip(h.groups), name),
+	}
+}
+
+// describe implements describer.
+func (h *redactLevelHandler) describe() string {
+	return "redact_level"
+}
+
+// unwrap implements describer.
+func (h *redactLevelHandler) unwrap() slog.Handler {
+	return h.next
+}
+
+// redactAttr applies the redaction to a, recursing into groups the way the
+// standard handlers call ReplaceAttr.
+func (h *redactLevelHandler) redactAttr(groups []string, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return h.replace(groups, a)
+	}
+	if a.Key != "" {
+		groups = append(slices.Clip(groups), a.Key)
+	}
+	attrs := a.Value.Group()
+	redacted := make([]slog.Attr, len(attrs))
+	for i, ga := range attrs {
+		redacted[i] = h.redactAttr(groups, ga)
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+}
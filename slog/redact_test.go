@@ -0,0 +1,58 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedactLevelHandler(t *testing.T) {
+	patterns, err := parseMaskPatterns(`secret-\w+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	config := &Config{
+		Level:        slog.LevelDebug,
+		HandlerType:  "bare",
+		HashKeys:     []string{"user.email"},
+		HashSalt:     "salt",
+		HashLength:   8,
+		MaskPatterns: patterns,
+		RedactLevel:  slog.LevelInfo,
+	}
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(config, &buf)).With("token", "secret-abc").WithGroup("user").With("email", "alice@example.com")
+
+	logger.Debug("login with secret-xyz", "email", "bob@example.com")
+	logger.Info("login with secret-xyz", "email", "bob@example.com")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	expected := `msg="login with secret-xyz" token=secret-abc user.email=alice@example.com user.email=bob@example.com`
+	if lines[0] != expected {
+		t.Errorf("expected debug line %q, got %q", expected, lines[0])
+	}
+	salt := []byte("salt")
+	expected = `msg="login with [MASKED]" token=[MASKED] user.email=` + hashValue(salt, "alice@example.com", 8) + ` user.email=` + hashValue(salt, "bob@example.com", 8)
+	if lines[1] != expected {
+		t.Errorf("expected info line %q, got %q", expected, lines[1])
+	}
+}
+
+func TestReadConfigRedactUnlessLevel(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerRedactUnlessLevel: "info"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.RedactLevel == nil || config.RedactLevel.Level() != slog.LevelInfo {
+		t.Errorf("expected redact level INFO, got %v", config.RedactLevel)
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerRedactUnlessLevel: "loud"}); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("expected ErrInvalidLevel, got %v", err)
+	}
+}
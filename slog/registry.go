@@ -0,0 +1,205 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLoggerNameKey is the attribute key that tags the records of
+// registered loggers with their name.
+const DefaultLoggerNameKey = "logger"
+
+var (
+	registryMu    sync.RWMutex
+	registry      = make(map[string]*slog.Logger)
+	loggerNameKey = DefaultLoggerNameKey
+)
+
+// SetLoggerNameKey sets the attribute key that RegisterLogger uses to tag the
+// records of registered loggers with their name. An empty key disables the
+// tagging. It affects loggers registered afterwards.
+func SetLoggerNameKey(key string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	loggerNameKey = key
+}
+
+// RegisterLogger registers a logger under the given name so that it can be
+// retrieved with GetLogger. Registering a name again replaces the previous logger.
+// The registered logger tags its records with its name, as a "logger"
+// attribute by default (see SetLoggerNameKey), so that the records of named
+// loggers are distinguishable in a shared stream.
+//
+// The handler of the registered logger is wrapped with mws in order, so the
+// last middleware sees the records first. They apply to this logger only,
+// for example to sample the records of a noisy logger but not those of the
+// audit logger. Nil middlewares are skipped.
+func RegisterLogger(name string, logger *slog.Logger, mws ...Middleware) {
+	if len(mws) > 0 {
+		handler := logger.Handler()
+		for _, mw := range mws {
+			if mw != nil {
+				handler = mw(handler)
+			}
+		}
+		logger = slog.New(handler)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if loggerNameKey != "" {
+		logger = logger.With(slog.String(loggerNameKey, name))
+	}
+	registry[name] = logger
+}
+
+// GetLogger returns the logger registered under the given name.
+// If no logger is registered under that name, it returns slog.Default().
+func GetLogger(name string) *slog.Logger {
+	if logger := lookupLogger(name); logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+// LogTo logs the same record to the loggers registered under the given names,
+// for example to write an audit event to both the audit and the application
+// stream from one call site. Each logger applies its own level and handler, so
+// a logger whose level is above the given level skips the record. Names that
+// are not registered resolve to slog.Default() as with GetLogger, and a logger
+// reached through several names logs the record once.
+func LogTo(names []string, level slog.Level, msg string, args ...any) {
+	logTo(context.Background(), names, level, msg, args...)
+}
+
+// LogToContext is like LogTo but passes ctx to each logger's handler, as with
+// LogAt.
+func LogToContext(ctx context.Context, names []string, level slog.Level, msg string, args ...any) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	logTo(ctx, names, level, msg, args...)
+}
+
+// logTo logs the record of LogTo and LogToContext, reporting the caller of the
+// exported function as the source.
+func logTo(ctx context.Context, names []string, level slog.Level, msg string, args ...any) {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip [runtime.Callers, logTo, LogTo]
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+
+	seen := make(map[*slog.Logger]bool, len(names))
+	for _, name := range names {
+		logger := GetLogger(name)
+		if seen[logger] {
+			continue
+		}
+		seen[logger] = true
+		if handler := logger.Handler(); handler.Enabled(ctx, level) {
+			_ = handler.Handle(ctx, r.Clone())
+		}
+	}
+}
+
+// lookupLogger returns the logger registered under the given name, or nil.
+func lookupLogger(name string) *slog.Logger {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[name]
+}
+
+// InitAll initializes the default logger and every named logger declared in
+// the environment.
+//
+// A named logger is declared by setting any logger variable with the name
+// inserted after the LOGGER_ prefix: LOGGER_<NAME>_LEVEL, LOGGER_<NAME>_HANDLER,
+// LOGGER_<NAME>_WRITER_FILE_PATH and so on. PLANKS_ENV_PREFIX applies as usual
+// (APP_LOGGER_<NAME>_LEVEL). Each named logger is configured only by its own
+// variables; settings not given for it use the package defaults rather than
+// the unnamed variables. Variables that exactly match an unnamed variable, such
+// as LOGGER_WRITER_FILE_PATH, always belong to the default logger.
+// Named loggers are registered under the lower-cased name, so LOGGER_DB_LEVEL
+// declares the logger returned by GetLogger("db").
+//
+// The default logger is configured from the unnamed variables exactly as Init
+// does, but InitAll never panics. Instead it returns the configuration errors
+// keyed by logger name, using "" for the default logger. It returns nil if
+// every logger was initialized successfully.
+func InitAll() map[string]error {
+	errs := make(map[string]error)
+	prefix := os.Getenv(EnvPlanksEnvPrefix)
+
+	if err := initDefault(); err != nil {
+		errs[""] = err
+	}
+
+	for _, name := range namedLoggerNames(prefix, os.Environ()) {
+		config, err := readConfig(func(key string) string {
+			return getEnv(prefix, namedEnvVar(name, key))
+		})
+		var logger *slog.Logger
+		if err == nil {
+			logger, err = buildLogger(config)
+		}
+		if err != nil {
+			errs[strings.ToLower(name)] = err
+			continue
+		}
+		RegisterLogger(strings.ToLower(name), logger)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// namedEnvVar returns the environment variable name of key for the named logger.
+func namedEnvVar(name, key string) string {
+	return "LOGGER_" + name + "_" + strings.TrimPrefix(key, "LOGGER_")
+}
+
+// namedLoggerNames scans environ ("KEY=value" entries) for variables declaring
+// named loggers and returns the distinct names in order of first appearance.
+func namedLoggerNames(prefix string, environ []string) []string {
+	if prefix != "" {
+		prefix += "_"
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		if value == "" || !strings.HasPrefix(key, prefix+"LOGGER_") {
+			continue
+		}
+		rest := strings.TrimPrefix(key, prefix+"LOGGER_")
+
+		name := ""
+		matched := 0
+		for _, envVar := range loggerEnvVars {
+			suffix := strings.TrimPrefix(envVar, "LOGGER_")
+			if rest == suffix {
+				// Unnamed variables belong to the default logger.
+				name = ""
+				break
+			}
+			if len(suffix) > matched && len(rest) > len(suffix)+1 && strings.HasSuffix(rest, "_"+suffix) {
+				name = rest[:len(rest)-len(suffix)-1]
+				matched = len(suffix)
+			}
+		}
+
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
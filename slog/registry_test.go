@@ -0,0 +1,206 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterLogger(t *testing.T) {
+	defer resetRegistry()
+
+	handler := newAttrBufferHandler()
+	RegisterLogger("db", slog.New(handler))
+
+	GetLogger("db").Info("query")
+	if expected := []string{"INFO: query [logger=db]"}; !reflect.DeepEqual(handler.logs, expected) {
+		t.Errorf("expected registered logger for 'db' tagged with its name, got %v", handler.logs)
+	}
+	if got := GetLogger("unknown"); got != slog.Default() {
+		t.Errorf("expected default logger for unregistered name")
+	}
+}
+
+func TestSetLoggerNameKey(t *testing.T) {
+	defer resetRegistry()
+	defer SetLoggerNameKey(DefaultLoggerNameKey)
+
+	var buf bytes.Buffer
+	SetLoggerNameKey("component")
+	RegisterLogger("db", slog.New(slog.NewTextHandler(&buf, nil)))
+	GetLogger("db").Info("query")
+	if !strings.Contains(buf.String(), "component=db") {
+		t.Errorf("expected component=db, got %q", buf.String())
+	}
+
+	// An empty key disables the tagging
+	SetLoggerNameKey("")
+	logger := slog.New(newTestBufferHandler())
+	RegisterLogger("cache", logger)
+	if GetLogger("cache") != logger {
+		t.Errorf("expected the logger to be registered unchanged")
+	}
+}
+
+func TestRegisterLoggerMiddlewares(t *testing.T) {
+	defer resetRegistry()
+
+	// The sampler keeps the first record only, as the rate of the
+	// records logged at a fixed time exceeds the target from then on.
+	sample := func(next slog.Handler) slog.Handler {
+		sampler := newAdaptiveSampler(1)
+		now := time.Now()
+		sampler.now = func() time.Time { return now }
+		sampler.rand = func() float64 { return 0.99 }
+		return newSamplerHandler(next, sampler)
+	}
+	app := newAttrBufferHandler()
+	audit := newAttrBufferHandler()
+	RegisterLogger("app", slog.New(app), sample, nil)
+	RegisterLogger("audit", slog.New(audit))
+
+	for range 10 {
+		GetLogger("app").Info("request")
+		GetLogger("audit").Info("login")
+	}
+	if len(app.logs) != 1 || app.logs[0] != "INFO: request [logger=app]" {
+		t.Errorf("expected the app logger to be sampled, got %v", app.logs)
+	}
+	if len(audit.logs) != 10 {
+		t.Errorf("expected every audit record, got %d", len(audit.logs))
+	}
+}
+
+func TestLogTo(t *testing.T) {
+	defer resetRegistry()
+
+	var audit, app bytes.Buffer
+	RegisterLogger("audit", slog.New(slog.NewTextHandler(&audit, &slog.HandlerOptions{ReplaceAttr: removeTime})))
+	RegisterLogger("app", slog.New(slog.NewTextHandler(&app, &slog.HandlerOptions{Level: slog.LevelWarn, ReplaceAttr: removeTime})))
+
+	LogTo([]string{"audit", "app"}, slog.LevelInfo, "login", "user", "alice")
+	LogToContext(context.Background(), []string{"audit", "app", "app"}, slog.LevelWarn, "locked", "user", "bob")
+
+	if expected := "level=INFO msg=login logger=audit user=alice\nlevel=WARN msg=locked logger=audit user=bob\n"; audit.String() != expected {
+		t.Errorf("expected %q, got %q", expected, audit.String())
+	}
+	if expected := "level=WARN msg=locked logger=app user=bob\n"; app.String() != expected {
+		t.Errorf("expected only the warning in the app log, logged once, got %q", app.String())
+	}
+}
+
+func TestLogToSource(t *testing.T) {
+	defer resetRegistry()
+
+	var buf bytes.Buffer
+	RegisterLogger("app", slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{AddSource: true})))
+
+	LogTo([]string{"app"}, slog.LevelInfo, "plain")
+	LogToContext(context.Background(), []string{"app"}, slog.LevelInfo, "with context")
+
+	if strings.Count(buf.String(), "registry_test.go:") != 2 {
+		t.Errorf("expected the caller as the source of both records, got %q", buf.String())
+	}
+}
+
+func TestNamedLoggerNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		environ  []string
+		expected []string
+	}{
+		{
+			name:     "Unnamed Variables Only",
+			environ:  []string{"LOGGER_LEVEL=debug", "LOGGER_WRITER_FILE_PATH=/tmp/a.log", "LOGGER_ADD_SOURCE=1"},
+			expected: nil,
+		},
+		{
+			name: "Multiple Named Groups",
+			environ: []string{
+				"LOGGER_DB_LEVEL=debug",
+				"LOGGER_AUDIT_LOG_WRITER_FILE_PATH=/tmp/audit.log",
+				"LOGGER_AUDIT_LOG_WRITER=file",
+				"LOGGER_DB_HANDLER=json",
+				"HOME=/root",
+			},
+			expected: []string{"DB", "AUDIT_LOG"},
+		},
+		{
+			name:     "Empty Values Ignored",
+			environ:  []string{"LOGGER_DB_LEVEL="},
+			expected: nil,
+		},
+		{
+			name:     "With Prefix",
+			prefix:   "APP",
+			environ:  []string{"APP_LOGGER_DB_LEVEL=debug", "LOGGER_OTHER_LEVEL=debug", "APP_LOGGER_LEVEL=info"},
+			expected: []string{"DB"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := namedLoggerNames(tt.prefix, tt.environ)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestInitAll(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	defer resetRegistry()
+
+	clearEnvVars()
+	t.Setenv(EnvLoggerHandler, "discard")
+	t.Setenv("LOGGER_DB_LEVEL", "debug")
+	t.Setenv("LOGGER_DB_HANDLER", "json")
+	t.Setenv("LOGGER_CACHE_LEVEL", "warn")
+	t.Setenv("LOGGER_BROKEN_HANDLER", "invalid")
+
+	errs := InitAll()
+
+	if len(errs) != 1 || !errors.Is(errs["broken"], ErrInvalidHandlerType) {
+		t.Errorf("expected only an invalid handler error for 'broken', got %v", errs)
+	}
+	if slog.Default() == originalDefault {
+		t.Errorf("expected default logger to be replaced")
+	}
+
+	db := lookupLogger("db")
+	if db == nil {
+		t.Fatalf("expected 'db' logger to be registered")
+	}
+	if !db.Enabled(t.Context(), slog.LevelDebug) {
+		t.Errorf("expected 'db' logger to enable debug level")
+	}
+
+	cache := lookupLogger("cache")
+	if cache == nil {
+		t.Fatalf("expected 'cache' logger to be registered")
+	}
+	if cache.Enabled(t.Context(), slog.LevelInfo) {
+		t.Errorf("expected 'cache' logger to disable info level")
+	}
+
+	if lookupLogger("broken") != nil {
+		t.Errorf("expected 'broken' logger not to be registered")
+	}
+}
+
+func resetRegistry() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = make(map[string]*slog.Logger)
+}
@@ -0,0 +1,51 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// parseRenameKeys parses a comma-separated list of "from=to" pairs into a map
+// from the original key to its replacement.
+func parseRenameKeys(s string) (map[string]string, error) {
+	renames := make(map[string]string)
+	for _, pair := range splitList(s) {
+		from, to, ok := strings.Cut(pair, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("%w: %q: want from=to", ErrInvalidRenameKeys, pair)
+		}
+		if _, dup := renames[from]; dup {
+			return nil, fmt.Errorf("%w: %q renamed more than once", ErrInvalidRenameKeys, from)
+		}
+		renames[from] = to
+	}
+	if len(renames) == 0 {
+		return nil, nil
+	}
+	return renames, nil
+}
+
+// replaceRenameKeys returns a ReplaceAttr function that renames the attributes
+// listed in the config's rename map, or nil if no renames are configured.
+//
+// Built-in and user attributes are matched alike by key. Attributes inside
+// groups are matched by their dotted path (for example "req.id"); the renamed
+// attribute stays in its group, so the replacement is a plain key.
+func replaceRenameKeys(config *Config) func([]string, slog.Attr) slog.Attr {
+	if len(config.RenameKeys) == 0 {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		path := a.Key
+		if len(groups) > 0 {
+			path = strings.Join(groups, ".") + "." + a.Key
+		}
+		if to, ok := config.RenameKeys[path]; ok {
+			a.Key = to
+		}
+		return a
+	}
+}
@@ -0,0 +1,95 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestReplaceRenameKeys(t *testing.T) {
+	var buf bytes.Buffer
+	config := &Config{HandlerType: "json", RenameKeys: map[string]string{
+		"time":   "@ts",
+		"level":  "severity",
+		"msg":    "message",
+		"user":   "user_id",
+		"req.id": "request_id",
+	}}
+	logger := slog.New(createHandler(config, &buf))
+
+	logger.Info("test message", "user", "u-1", slog.Group("req", "id", "r-1", "path", "/"))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+	}
+	if _, ok := record["@ts"]; !ok {
+		t.Errorf("expected time under @ts, got %v", record)
+	}
+	delete(record, "@ts")
+
+	expected := map[string]any{
+		"severity": "INFO",
+		"message":  "test message",
+		"user_id":  "u-1",
+		"req":      map[string]any{"request_id": "r-1", "path": "/"},
+	}
+	if !reflect.DeepEqual(record, expected) {
+		t.Errorf("expected %v, got %v", expected, record)
+	}
+}
+
+func TestParseRenameKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{name: "empty", input: "", expected: nil},
+		{name: "pairs", input: "time=@ts, level = severity,,", expected: map[string]string{"time": "@ts", "level": "severity"}},
+		{name: "missing equals", input: "time", wantErr: true},
+		{name: "empty source", input: "=ts", wantErr: true},
+		{name: "empty target", input: "time=", wantErr: true},
+		{name: "duplicate", input: "time=a,time=b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renames, err := parseRenameKeys(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRenameKeys) {
+					t.Errorf("expected ErrInvalidRenameKeys, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(renames, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, renames)
+			}
+		})
+	}
+}
+
+func TestReadConfigRenameKeys(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerRenameKeys: "msg=message"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := map[string]string{"msg": "message"}; !reflect.DeepEqual(config.RenameKeys, expected) {
+		t.Errorf("expected %v, got %v", expected, config.RenameKeys)
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerRenameKeys: "msg"}); !errors.Is(err, ErrInvalidRenameKeys) {
+		t.Errorf("expected ErrInvalidRenameKeys, got %v", err)
+	}
+}
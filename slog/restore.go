@@ -0,0 +1,61 @@
+package slog
+
+import (
+	"io"
+	"log"
+	"log/slog"
+)
+
+// previousDefault is the default logger setup that Init replaced, together
+// with the output and flags of the log package, which slog.SetDefault
+// redirects.
+type previousDefault struct {
+	logger    *slog.Logger
+	logWriter io.Writer
+	logFlags  int
+}
+
+// preInit is the default logger setup before Init first installed its
+// default logger, or nil. It is guarded by installedMu.
+var preInit *previousDefault
+
+// capturePreInit records the current default logger setup as the one to
+// restore with Restore, unless one has been recorded already. The capturing
+// logger of CaptureEarly is skipped in favor of the logger it replaced.
+// installedMu must be held.
+func capturePreInit() {
+	if preInit != nil {
+		return
+	}
+	if h, ok := slog.Default().Handler().(*earlyHandler); ok {
+		preInit = &previousDefault{logger: h.buf.previous, logWriter: h.buf.logWriter, logFlags: h.buf.logFlags}
+		return
+	}
+	preInit = &previousDefault{logger: slog.Default(), logWriter: log.Writer(), logFlags: log.Flags()}
+}
+
+// Restore reinstalls the default logger that was in place before Init first
+// installed its own, restores the output and flags of the log package, and
+// releases the resources the package set up, as Close does, returning its
+// error. Afterwards CurrentConfig returns nil, and a later Init installs a new
+// default logger. If Init has not installed a default logger, Restore only
+// calls Close.
+//
+// Restore is meant for applications that install the logger of this package
+// temporarily. Loggers derived from the replaced default logger keep logging
+// with its configuration until Close releases their files.
+func Restore() error {
+	installedMu.Lock()
+	prev := preInit
+	preInit = nil
+	if prev != nil {
+		installedRoot, installedSwappable = nil, nil
+		slog.SetDefault(prev.logger)
+		log.SetOutput(prev.logWriter)
+		log.SetFlags(prev.logFlags)
+		currentConfig.Store(nil)
+	}
+	installedMu.Unlock()
+
+	return Close()
+}
@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestore(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Snapshot()()
+
+	// Forget the logger recorded by the Init calls of other tests.
+	installedMu.Lock()
+	preInit, installedRoot, installedSwappable = nil, nil, nil
+	installedMu.Unlock()
+
+	var buf bytes.Buffer
+	original := slog.New(slog.NewTextHandler(&buf, nil))
+	slog.SetDefault(original)
+	logWriter, logFlags := log.Writer(), log.Flags()
+
+	clearEnvVars()
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, filepath.Join(t.TempDir(), "test.log"))
+	Init()
+	// A second Init reconfigures in place and keeps the original for Restore.
+	Init()
+	if slog.Default() == original {
+		t.Fatal("expected Init to replace the default logger")
+	}
+
+	if err := Restore(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slog.Default() != original {
+		t.Error("expected the original default logger to be reinstalled")
+	}
+	if log.Writer() != logWriter || log.Flags() != logFlags {
+		t.Error("expected the log package output to be restored")
+	}
+	if CurrentConfig() != nil {
+		t.Error("expected no current config after Restore")
+	}
+	closersMu.Lock()
+	open := 0
+	for _, fns := range closers {
+		open += len(fns)
+	}
+	closersMu.Unlock()
+	if open != 0 {
+		t.Errorf("expected the resources to be released, %d cleanups left", open)
+	}
+
+	// Without an installed logger, Restore leaves the default alone.
+	if err := Restore(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if slog.Default() != original {
+		t.Error("expected a second Restore to keep the default logger")
+	}
+}
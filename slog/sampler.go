@@ -0,0 +1,121 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// The adaptive sampler estimates the incoming record rate over a sliding
+// window of adaptiveSampleBuckets buckets of adaptiveSampleBucketWidth each.
+// A longer window smooths out bursts at the cost of reacting more slowly to
+// changes in load.
+const (
+	adaptiveSampleBuckets     = 10
+	adaptiveSampleBucketWidth = time.Second
+)
+
+// adaptiveSampler decides which records to keep so that the output rate stays
+// near a target rate. Each record is kept with probability target/rate, where
+// rate is the incoming rate (kept and dropped records alike) over the sliding
+// window. While the rate is at or below the target, every record is kept.
+type adaptiveSampler struct {
+	mu     sync.Mutex
+	target float64
+	now    func() time.Time
+	rand   func() float64
+
+	start  time.Time
+	counts [adaptiveSampleBuckets]int
+	slots  [adaptiveSampleBuckets]int64 // bucket number each count belongs to
+}
+
+// newAdaptiveSampler creates an adaptive sampler aiming at target records per second.
+func newAdaptiveSampler(target float64) *adaptiveSampler {
+	return &adaptiveSampler{
+		target: target,
+		now:    time.Now,
+		rand:   rand.Float64,
+	}
+}
+
+// sample records an incoming record and reports whether it should be kept.
+func (s *adaptiveSampler) sample() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.start.IsZero() {
+		s.start = now
+	}
+	bucket := now.UnixNano() / int64(adaptiveSampleBucketWidth)
+	i := bucket % adaptiveSampleBuckets
+	if s.slots[i] != bucket {
+		s.slots[i] = bucket
+		s.counts[i] = 0
+	}
+	s.counts[i]++
+
+	total := 0
+	for j := range s.counts {
+		if bucket-s.slots[j] < adaptiveSampleBuckets {
+			total += s.counts[j]
+		}
+	}
+	// Until a full window has elapsed, average over the time seen so far.
+	elapsed := min(max(now.Sub(s.start), adaptiveSampleBucketWidth), adaptiveSampleBuckets*adaptiveSampleBucketWidth)
+	rate := float64(total) / elapsed.Seconds()
+
+	if rate <= s.target {
+		return true
+	}
+	return s.rand() < s.target/rate
+}
+
+// samplerHandler is a wrapper handler that drops records not kept by its
+// sampler. Handlers derived with WithAttrs and WithGroup share the sampler.
+type samplerHandler struct {
+	next    slog.Handler
+	sampler *adaptiveSampler
+}
+
+// newSamplerHandler creates a new handler that samples records with sampler.
+func newSamplerHandler(next slog.Handler, sampler *adaptiveSampler) slog.Handler {
+	return &samplerHandler{next: next, sampler: sampler}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *samplerHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *samplerHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.sampler.sample() {
+		reportDrop(r, DropReasonSampled)
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *samplerHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplerHandler{next: h.next.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *samplerHandler) WithGroup(name string) slog.Handler {
+	return &samplerHandler{next: h.next.WithGroup(name), sampler: h.sampler}
+}
+
+// describe implements describer.
+func (h *samplerHandler) describe() string {
+	return "sampler"
+}
+
+// unwrap implements describer.
+func (h *samplerHandler) unwrap() slog.Handler {
+	return h.next
+}
@@ -0,0 +1,111 @@
+package slog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+)
+
+// simulateLoad feeds sampler rps evenly spaced records per second for the
+// given number of seconds, advancing *now, and returns the number of records
+// kept in each second.
+func simulateLoad(s *adaptiveSampler, now *time.Time, rps, seconds int) []int {
+	kept := make([]int, seconds)
+	step := time.Second / time.Duration(rps)
+	for sec := range seconds {
+		for range rps {
+			if s.sample() {
+				kept[sec]++
+			}
+			*now = now.Add(step)
+		}
+	}
+	return kept
+}
+
+func TestAdaptiveSamplerVariableLoad(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newAdaptiveSampler(100)
+	s.now = func() time.Time { return now }
+	s.rand = rand.New(rand.NewPCG(1, 2)).Float64
+
+	// Under the target, everything is kept
+	for sec, n := range simulateLoad(s, &now, 50, 5) {
+		if n != 50 {
+			t.Errorf("low load, second %d: expected all 50 records kept, got %d", sec, n)
+		}
+	}
+
+	// Once the window reflects a high load, output is held near the target
+	high := simulateLoad(s, &now, 1000, 20)
+	for sec, n := range high[adaptiveSampleBuckets:] {
+		if n < 70 || n > 130 {
+			t.Errorf("high load, second %d: expected about 100 records kept, got %d", sec+adaptiveSampleBuckets, n)
+		}
+	}
+
+	// After the load drops and the window has passed, everything is kept again
+	low := simulateLoad(s, &now, 50, 20)
+	for sec, n := range low[adaptiveSampleBuckets:] {
+		if n != 50 {
+			t.Errorf("low load again, second %d: expected all 50 records kept, got %d", sec+adaptiveSampleBuckets, n)
+		}
+	}
+}
+
+func TestSamplerHandlerReportsDrops(t *testing.T) {
+	defer SetDropCallback(nil)
+
+	var reasons []string
+	SetDropCallback(func(r slog.Record, reason string) {
+		reasons = append(reasons, reason)
+	})
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := newAdaptiveSampler(1)
+	sampler.now = func() time.Time { return now }
+	sampler.rand = func() float64 { return 0.99 }
+
+	var buf bytes.Buffer
+	logger := slog.New(newSamplerHandler(slog.NewTextHandler(&buf, nil), sampler)).With("k", "v")
+	logger.Info("kept")
+	logger.Info("dropped")
+
+	if !strings.Contains(buf.String(), "kept") || strings.Contains(buf.String(), "dropped") {
+		t.Errorf("expected only the first record, got %q", buf.String())
+	}
+	if len(reasons) != 1 || reasons[0] != DropReasonSampled {
+		t.Errorf("expected one %q drop, got %v", DropReasonSampled, reasons)
+	}
+}
+
+func TestReadConfigSampling(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{
+		EnvLoggerSampleAdaptive: "true",
+		EnvLoggerSampleTarget:   "250.5",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.SampleAdaptive || config.SampleTargetRPS != 250.5 {
+		t.Errorf("unexpected sampling config: %v %v", config.SampleAdaptive, config.SampleTargetRPS)
+	}
+
+	for _, target := range []string{"", "0", "-1", "fast", "NaN", "+Inf"} {
+		_, err := readConfigFromEnv(t, map[string]string{
+			EnvLoggerSampleAdaptive: "true",
+			EnvLoggerSampleTarget:   target,
+		})
+		if !errors.Is(err, ErrInvalidSampling) {
+			t.Errorf("target %q: expected ErrInvalidSampling, got %v", target, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package slog
+
+import (
+	"errors"
+	"strings"
+)
+
+// Severity classifies configuration errors by how they are handled.
+type Severity int
+
+const (
+	// SeverityHard is the severity of configuration errors that prevent
+	// building the logger, such as an unparseable level. They always fail the
+	// build, and Init panics on them unless PLANKS_NO_PANIC_ON_ERROR is set.
+	SeverityHard Severity = iota
+	// SeveritySoft is the severity of configuration problems that the package
+	// can degrade gracefully, such as a time zone missing from the system.
+	// They are logged as a warning through the built logger and the build
+	// continues with a fallback, unless PLANKS_STRICT=soft fails them as well.
+	SeveritySoft
+)
+
+// String returns the name of the severity: "hard" or "soft".
+func (s Severity) String() string {
+	if s == SeveritySoft {
+		return "soft"
+	}
+	return "hard"
+}
+
+// softErrors lists the errors of soft severity. All other errors are hard.
+var softErrors = []error{
+	ErrInvalidTimezone,
+	ErrMissingHashSalt,
+	ErrLevelFallback,
+}
+
+// ErrorSeverity returns the severity of a configuration error returned by
+// ReadConfig, Build or their variants. Errors not originating from the
+// package are hard.
+func ErrorSeverity(err error) Severity {
+	for _, soft := range softErrors {
+		if errors.Is(err, soft) {
+			return SeveritySoft
+		}
+	}
+	return SeverityHard
+}
+
+// parseStrict reports whether errors of soft severity fail the build for the
+// value of PLANKS_STRICT. The value names the lowest severity that fails:
+// empty or "hard" fails hard errors only, and "soft" fails soft errors too.
+// Any other value is treated as "soft", since PLANKS_STRICT was originally an
+// on/off switch.
+func parseStrict(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", SeverityHard.String():
+		return false
+	default:
+		return true
+	}
+}
+
+// softError handles a configuration problem of soft severity. In strict mode
+// it returns err; otherwise it records a warning with msg, args and err on the
+// config and returns nil, so that the caller continues with a fallback.
+func (c *Config) softError(err error, msg string, args ...any) error {
+	if c.Strict {
+		return err
+	}
+	c.addWarning(msg, append(args, "error", err)...)
+	return nil
+}
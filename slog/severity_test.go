@@ -0,0 +1,94 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestErrorSeverity(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected Severity
+	}{
+		{ErrInvalidLevel, SeverityHard},
+		{fmt.Errorf("%w: %q", ErrInvalidHandlerType, "xml"), SeverityHard},
+		{errors.New("other"), SeverityHard},
+		{ErrInvalidTimezone, SeveritySoft},
+		{fmt.Errorf("%w: %w", ErrInvalidTimezone, errors.New("unknown time zone")), SeveritySoft},
+	}
+	for _, tt := range tests {
+		if got := ErrorSeverity(tt.err); got != tt.expected {
+			t.Errorf("%v: expected %v, got %v", tt.err, tt.expected, got)
+		}
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	tests := map[string]bool{
+		"":     false,
+		"hard": false,
+		"HARD": false,
+		"soft": true,
+		"true": true,
+		"1":    true,
+	}
+	for value, expected := range tests {
+		if got := parseStrict(value); got != expected {
+			t.Errorf("%q: expected %v, got %v", value, expected, got)
+		}
+	}
+}
+
+// initPanics reports whether Init panics.
+func initPanics() (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	Init()
+	return false
+}
+
+func TestInitSeverity(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	defer Close()
+
+	for _, strict := range []string{"", "hard"} {
+		path := filepath.Join(t.TempDir(), "test.log")
+
+		// A soft error is logged as a warning
+		clearEnvVars()
+		t.Setenv(EnvPlanksStrict, strict)
+		t.Setenv(EnvLoggerWriter, "file")
+		t.Setenv(EnvLoggerWriterFilePath, path)
+		t.Setenv(EnvLoggerTimezone, "Nowhere/Missing")
+		if initPanics() {
+			t.Fatalf("PLANKS_STRICT=%q: expected no panic on a soft error", strict)
+		}
+		data, _ := os.ReadFile(path)
+		if !strings.Contains(string(data), "failed to load timezone") {
+			t.Errorf("PLANKS_STRICT=%q: expected a warning, got %q", strict, data)
+		}
+
+		// A hard error panics under the same settings
+		t.Setenv(EnvLoggerLevel, "loud")
+		if !initPanics() {
+			t.Errorf("PLANKS_STRICT=%q: expected a panic on a hard error", strict)
+		}
+	}
+
+	// PLANKS_STRICT=soft panics on soft errors as well
+	clearEnvVars()
+	t.Setenv(EnvPlanksStrict, "soft")
+	t.Setenv(EnvLoggerTimezone, "Nowhere/Missing")
+	if !initPanics() {
+		t.Errorf("expected a panic on a soft error with PLANKS_STRICT=soft")
+	}
+}
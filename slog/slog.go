@@ -11,9 +11,12 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Default values for the logger configuration.
@@ -34,8 +37,70 @@ var (
 	ErrInvalidWriterType = errors.New("invalid writer type")
 	// ErrMissingFilePath is returned when file writer is specified but no file path is provided.
 	ErrMissingFilePath = errors.New("file path is required when writer type is 'file'")
+	// ErrMissingUnixSocketPath is returned when the unixsocket writer is specified but no socket path is provided.
+	ErrMissingUnixSocketPath = errors.New("socket path is required when writer type is 'unixsocket'")
+	// ErrInvalidUnixSocketType is returned when an invalid Unix domain socket type is specified.
+	ErrInvalidUnixSocketType = errors.New("invalid unix socket type")
 	// ErrInvalidFilePermission is returned when an invalid file permission is specified.
 	ErrInvalidFilePermission = errors.New("invalid file permission")
+	// ErrWriterProbe is returned when the test write to the configured writer fails.
+	ErrWriterProbe = errors.New("writer probe failed")
+	// ErrInvalidFraming is returned when an invalid framing type is specified.
+	ErrInvalidFraming = errors.New("invalid framing type")
+	// ErrInvalidWriterBreaker is returned when an invalid writer circuit breaker setting is specified.
+	ErrInvalidWriterBreaker = errors.New("invalid writer circuit breaker setting")
+	// ErrInvalidRenameKeys is returned when the key rename list is malformed.
+	ErrInvalidRenameKeys = errors.New("invalid rename keys")
+	// ErrInvalidSampling is returned when an invalid sampling setting is specified.
+	ErrInvalidSampling = errors.New("invalid sampling setting")
+	// ErrInvalidAudit is returned when the audit settings are malformed or incomplete.
+	ErrInvalidAudit = errors.New("invalid audit settings")
+	// ErrInvalidOutputs is returned when the output list is malformed.
+	ErrInvalidOutputs = errors.New("invalid outputs")
+	// ErrInvalidMaxAttrs is returned when an invalid attribute limit is specified.
+	ErrInvalidMaxAttrs = errors.New("invalid maximum number of attributes")
+	// ErrInvalidMaxCollectionLen is returned when an invalid collection length limit is specified.
+	ErrInvalidMaxCollectionLen = errors.New("invalid maximum collection length")
+	// ErrContextLoggerCycle is returned by strict context-aware handlers when
+	// context loggers re-enter each other.
+	ErrContextLoggerCycle = errors.New("context logger cycle")
+	// ErrInvalidOpts is returned when the combined LOGGER_OPTS settings are malformed.
+	ErrInvalidOpts = errors.New("invalid logger options")
+	// ErrInvalidPreset is returned when an unknown preset is specified.
+	ErrInvalidPreset = errors.New("invalid preset")
+	// ErrInvalidTimezone is returned in strict mode when the timezone cannot be loaded.
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	// ErrInvalidDedupPolicy is returned when an invalid key de-duplication policy is specified.
+	ErrInvalidDedupPolicy = errors.New("invalid key de-duplication policy")
+	// ErrInvalidAttrsFromEnv is returned when the list of attributes from environment variables is malformed.
+	ErrInvalidAttrsFromEnv = errors.New("invalid attributes from environment variables")
+	// ErrInvalidHeartbeatInterval is returned when an invalid heartbeat interval is specified.
+	ErrInvalidHeartbeatInterval = errors.New("invalid heartbeat interval")
+	// ErrInvalidWriteTimeout is returned when an invalid write timeout is specified.
+	ErrInvalidWriteTimeout = errors.New("invalid write timeout")
+	// ErrInvalidHashLength is returned when an invalid hash length is specified.
+	ErrInvalidHashLength = errors.New("invalid hash length")
+	// ErrMissingHashSalt is a soft error reported when attribute values are
+	// hashed without a salt, which makes the hashes open to dictionary attacks.
+	ErrMissingHashSalt = errors.New("hash salt not set")
+	// ErrLevelFallback is a soft error reported when LOGGER_LEVEL cannot be
+	// parsed and the level of PLANKS_LEVEL_FALLBACK is used instead.
+	ErrLevelFallback = errors.New("invalid level, using fallback level")
+	// ErrInvalidBrokenPipeAction is returned when an invalid broken pipe action is specified.
+	ErrInvalidBrokenPipeAction = errors.New("invalid broken pipe action")
+	// ErrInvalidJSONArray is returned when JSON array output is requested
+	// without a JSON file output or with length framing.
+	ErrInvalidJSONArray = errors.New("JSON array output requires the json handler, a file writer and no length framing")
+	// ErrInvalidMaskPattern is returned when a mask pattern is not a valid regular expression.
+	ErrInvalidMaskPattern = errors.New("invalid mask pattern")
+	// ErrInvalidMaxLine is returned when an invalid line length limit or action is specified.
+	ErrInvalidMaxLine = errors.New("invalid maximum line length")
+	// ErrInvalidMsgLevelOverride is returned when a message level override is not "substring=level".
+	ErrInvalidMsgLevelOverride = errors.New("invalid message level override")
+	// ErrInvalidNilAttrAction is returned when an invalid nil attribute action is specified.
+	ErrInvalidNilAttrAction = errors.New("invalid nil attribute action")
+	// ErrInvalidNoteSuppressed is returned when an invalid suppressed record count is specified.
+	ErrInvalidNoteSuppressed = errors.New("invalid suppressed record count")
 )
 
 // Environment variable names used for configuration.
@@ -43,12 +108,68 @@ const (
 	EnvLoggerLevel          = "LOGGER_LEVEL"
 	EnvLoggerAddSource      = "LOGGER_ADD_SOURCE"
 	EnvLoggerHandler        = "LOGGER_HANDLER"
+	EnvLoggerJSONHTMLEscape = "LOGGER_JSON_HTML_ESCAPE"
+	EnvLoggerJSONFast       = "LOGGER_JSON_FAST"
+	EnvLoggerGELFHost       = "LOGGER_GELF_HOST"
+	EnvLoggerJSONArray      = "LOGGER_JSON_ARRAY"
 	EnvLoggerWriter         = "LOGGER_WRITER"
 	EnvLoggerWriterFilePath = "LOGGER_WRITER_FILE_PATH"
 	EnvLoggerWriterNoAppend = "LOGGER_WRITER_FILE_NO_APPEND"
 	EnvLoggerWriterFilePerm = "LOGGER_WRITER_FILE_PERM"
+	EnvLoggerAddGoID        = "LOGGER_ADD_GOID"
+	EnvLoggerAddUptime      = "LOGGER_ADD_UPTIME"
+	EnvLoggerAddModule      = "LOGGER_ADD_MODULE"
+	EnvLoggerNilAttrAction  = "LOGGER_NIL_ATTR_ACTION"
+	EnvLoggerUnixSocketPath = "LOGGER_UNIX_SOCKET_PATH"
+	EnvLoggerUnixSocketType = "LOGGER_UNIX_SOCKET_TYPE"
+	EnvLoggerStackOnce      = "LOGGER_STACKTRACE_ONCE"
+	EnvLoggerSourceKey      = "LOGGER_SOURCE_KEY"
+	EnvLoggerSourceFlatten  = "LOGGER_SOURCE_FLATTEN"
+	EnvLoggerSourceLevel    = "LOGGER_SOURCE_LEVEL"
+	EnvLoggerWriterProbe    = "LOGGER_WRITER_PROBE"
+	EnvLoggerFraming        = "LOGGER_FRAMING"
+	EnvLoggerAttrAllowlist  = "LOGGER_ATTR_ALLOWLIST"
+	EnvLoggerRenameKeys     = "LOGGER_RENAME_KEYS"
+	EnvLoggerPIDFile        = "LOGGER_PIDFILE"
+	EnvLoggerSampleAdaptive = "LOGGER_SAMPLE_ADAPTIVE"
+	EnvLoggerSampleTarget   = "LOGGER_SAMPLE_TARGET_RPS"
+	EnvLoggerOutputs        = "LOGGER_OUTPUTS"
+	EnvLoggerAuditMarkerKey = "LOGGER_AUDIT_MARKER_KEY"
+	EnvLoggerAuditOutput    = "LOGGER_AUDIT_OUTPUT"
+	EnvLoggerMaxAttrs       = "LOGGER_MAX_ATTRS"
+	EnvLoggerContextStrict  = "LOGGER_CONTEXT_STRICT"
+	EnvLoggerPreset         = "LOGGER_PRESET"
+	EnvLoggerOpts           = "LOGGER_OPTS"
+	EnvLoggerTimezone       = "LOGGER_TIMEZONE"
+	EnvLoggerDedupKeys      = "LOGGER_DEDUP_KEYS"
+	EnvLoggerAttrsFromEnv   = "LOGGER_ATTRS_FROM_ENV"
+	EnvLoggerWriteTimeout   = "LOGGER_WRITE_TIMEOUT"
+	EnvLoggerHashKeys       = "LOGGER_HASH_KEYS"
+	EnvLoggerHashSalt       = "LOGGER_HASH_SALT"
+	EnvLoggerHashLength     = "LOGGER_HASH_LENGTH"
+	EnvLoggerMaskPatterns   = "LOGGER_MASK_PATTERNS"
+	EnvLoggerSortAttrs      = "LOGGER_SORT_ATTRS"
+	EnvLoggerCtxCause       = "LOGGER_CTX_CAUSE"
+	EnvLoggerMaxLineBytes   = "LOGGER_MAX_LINE_BYTES"
+	EnvLoggerMaxLineAction  = "LOGGER_MAX_LINE_ACTION"
+
+	EnvLoggerWriterBreakerThreshold = "LOGGER_WRITER_BREAKER_THRESHOLD"
+	EnvLoggerWriterBreakerCooldown  = "LOGGER_WRITER_BREAKER_COOLDOWN"
+	EnvLoggerHeartbeatInterval      = "LOGGER_HEARTBEAT_INTERVAL"
+	EnvLoggerProtectBuiltins        = "LOGGER_PROTECT_BUILTINS"
+	EnvLoggerNoteSuppressed         = "LOGGER_NOTE_SUPPRESSED"
+	EnvLoggerMaxCollectionLen       = "LOGGER_MAX_COLLECTION_LEN"
+	EnvLoggerBrokenPipeAction       = "LOGGER_BROKEN_PIPE_ACTION"
+	EnvLoggerAuditStripMarker       = "LOGGER_AUDIT_STRIP_MARKER"
+	EnvLoggerRedactUnlessLevel      = "LOGGER_REDACT_UNLESS_LEVEL"
+	EnvLoggerWriterFileIndex        = "LOGGER_WRITER_FILE_INDEX"
+	EnvLoggerMsgLevelOverrides      = "LOGGER_MSG_LEVEL_OVERRIDES"
+	EnvLoggerNilAttrPlaceholder     = "LOGGER_NIL_ATTR_PLACEHOLDER"
+
 	EnvPlanksNoPanicOnError = "PLANKS_NO_PANIC_ON_ERROR"
 	EnvPlanksEnvPrefix      = "PLANKS_ENV_PREFIX"
+	EnvPlanksStrict         = "PLANKS_STRICT"
+	EnvPlanksLevelFallback  = "PLANKS_LEVEL_FALLBACK"
 )
 
 // ContextLoggerKey is a key for context.Context values. It is used to store
@@ -59,38 +180,121 @@ type ContextLoggerKey struct{}
 // contextAwareHandler is a wrapper handler that checks for a logger in the context
 // and delegates logging to that logger's handler if found. Otherwise, it delegates
 // to its internal handler.
+//
+// A context logger whose handler is this handler itself is handled directly by
+// the internal handler, so storing a logger in the context and logging through
+// that same logger is a no-op. A context logger whose handler leads back to a
+// context-aware handler, for example a wrapper around the default logger's
+// handler, receives each record once: the context passed on during delegation,
+// and by the context loggers created by this package when they are logged
+// through directly, is marked with the context logger, and a context-aware
+// handler reached with the context logger marked as the innermost one entered
+// uses the internal handler. A context logger that is reached again after
+// another one was entered, which would recurse forever, is a cycle; it falls
+// back to the internal handler, or fails with ErrContextLoggerCycle in strict
+// mode.
+//
+// With a dedup policy, records passed to a context logger have their
+// attributes, including the lazy context attributes, de-duplicated before
+// delegation, because the context logger may not remove duplicate keys
+// itself. Attributes the context logger adds on its own, once it handles the
+// record, are only de-duplicated if it was created from a logger with the
+// same policy.
 type contextAwareHandler struct {
 	internal slog.Handler
+	strict   bool
+	dedup    string
 }
 
-// Enabled implements slog.Handler.Enabled.
-func (h *contextAwareHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	if ctx != nil {
-		if loggerValue := ctx.Value(ContextLoggerKey{}); loggerValue != nil {
-			if logger, ok := loggerValue.(*slog.Logger); ok && logger != nil {
-				// Ensure handler is not itself to prevent recursive loops
-				contextHandler := logger.Handler()
-				if contextHandler != h {
-					return contextHandler.Enabled(ctx, level)
-				}
+// contextDelegationKey is the context key under which the context loggers a
+// record entered are kept, as a *contextDelegation.
+type contextDelegationKey struct{}
+
+// contextDelegation is a context logger a record entered, identified by key,
+// and the ones it entered before.
+type contextDelegation struct {
+	key    any
+	parent *contextDelegation
+	// delegated reports whether the context logger was entered by a
+	// contextAwareHandler rather than logged through directly.
+	delegated bool
+}
+
+// enterContextLogger returns a copy of ctx marked as having entered the
+// context logger identified by key.
+func enterContextLogger(ctx context.Context, key any, delegated bool) context.Context {
+	parent, _ := ctx.Value(contextDelegationKey{}).(*contextDelegation)
+	return context.WithValue(ctx, contextDelegationKey{}, &contextDelegation{key: key, parent: parent, delegated: delegated})
+}
+
+// isDelegated reports whether a contextAwareHandler passed ctx on to a context
+// logger.
+func isDelegated(ctx context.Context) bool {
+	for d, _ := ctx.Value(contextDelegationKey{}).(*contextDelegation); d != nil; d = d.parent {
+		if d.delegated {
+			return true
+		}
+	}
+	return false
+}
+
+// contextHandler returns the handler of the context logger to delegate to, and
+// the context to delegate with, or a nil handler if the internal handler should
+// be used. cycle reports whether delegating would re-enter a context logger
+// that was entered before another one.
+func (h *contextAwareHandler) contextHandler(ctx context.Context) (handler slog.Handler, delegated context.Context, cycle bool) {
+	if ctx == nil {
+		return nil, nil, false
+	}
+	logger, ok := ctx.Value(ContextLoggerKey{}).(*slog.Logger)
+	if !ok || logger == nil {
+		return nil, nil, false
+	}
+	// Ensure handler is not itself to prevent recursive loops
+	contextHandler := logger.Handler()
+	if contextHandler == h {
+		return nil, nil, false
+	}
+
+	key := contextLoggerKey(logger)
+	if d, _ := ctx.Value(contextDelegationKey{}).(*contextDelegation); d != nil {
+		// The record already went through the context logger
+		if d.key == key {
+			return nil, nil, false
+		}
+		for d = d.parent; d != nil; d = d.parent {
+			if d.key == key {
+				return nil, nil, true
 			}
 		}
 	}
+	return contextHandler, enterContextLogger(ctx, key, true), false
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *contextAwareHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if contextHandler, delegated, _ := h.contextHandler(ctx); contextHandler != nil {
+		return contextHandler.Enabled(delegated, level)
+	}
 	return h.internal.Enabled(ctx, level)
 }
 
 // Handle implements slog.Handler.Handle.
 func (h *contextAwareHandler) Handle(ctx context.Context, r slog.Record) error {
-	if ctx != nil {
-		if loggerValue := ctx.Value(ContextLoggerKey{}); loggerValue != nil {
-			if logger, ok := loggerValue.(*slog.Logger); ok && logger != nil {
-				// Ensure handler is not itself to prevent recursive loops
-				contextHandler := logger.Handler()
-				if contextHandler != h {
-					return contextHandler.Handle(ctx, r)
-				}
-			}
+	// The lazy attributes are added by the first context-aware handler
+	// only, not again by the handlers it delegates to.
+	if ctx != nil && !isDelegated(ctx) {
+		r = addLazyAttrs(ctx, r)
+	}
+	contextHandler, delegated, cycle := h.contextHandler(ctx)
+	if cycle && h.strict {
+		return ErrContextLoggerCycle
+	}
+	if contextHandler != nil {
+		if h.dedup != "" {
+			r = dedupRecord(r, h.dedup)
 		}
+		return contextHandler.Handle(delegated, r)
 	}
 	return h.internal.Handle(ctx, r)
 }
@@ -99,6 +303,8 @@ func (h *contextAwareHandler) Handle(ctx context.Context, r slog.Record) error {
 func (h *contextAwareHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &contextAwareHandler{
 		internal: h.internal.WithAttrs(attrs),
+		strict:   h.strict,
+		dedup:    h.dedup,
 	}
 }
 
@@ -106,9 +312,24 @@ func (h *contextAwareHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 func (h *contextAwareHandler) WithGroup(name string) slog.Handler {
 	return &contextAwareHandler{
 		internal: h.internal.WithGroup(name),
+		strict:   h.strict,
+		dedup:    h.dedup,
 	}
 }
 
+// describe implements describer.
+func (h *contextAwareHandler) describe() string {
+	if h.strict {
+		return "context(strict)"
+	}
+	return "context"
+}
+
+// unwrap implements describer.
+func (h *contextAwareHandler) unwrap() slog.Handler {
+	return h.internal
+}
+
 // newContextAwareHandler creates a new handler that wraps the given handler
 // with context-aware functionality.
 func newContextAwareHandler(handler slog.Handler) slog.Handler {
@@ -117,6 +338,15 @@ func newContextAwareHandler(handler slog.Handler) slog.Handler {
 	}
 }
 
+// newStrictContextAwareHandler is like newContextAwareHandler, but the
+// returned handler fails with ErrContextLoggerCycle on context logger cycles.
+func newStrictContextAwareHandler(handler slog.Handler) slog.Handler {
+	return &contextAwareHandler{
+		internal: handler,
+		strict:   true,
+	}
+}
+
 func FromContext(ctx context.Context) *slog.Logger {
 	if loggerValue := ctx.Value(ContextLoggerKey{}); loggerValue != nil {
 		if logger, ok := loggerValue.(*slog.Logger); ok && logger != nil {
@@ -130,63 +360,274 @@ func FromContext(ctx context.Context) *slog.Logger {
 type Config struct {
 	// Level is the minimum level to log.
 	Level slog.Level
+	// NoteSuppressed, if positive, is the number of records of a level below
+	// the minimum level after which a note on their suppression is logged.
+	// Only records that reach the handler are counted, such as those demoted
+	// by MsgLevelOverrides; calls skipped because their level is disabled are
+	// not.
+	NoteSuppressed int
+	// MsgLevelOverrides change the level of records by their message. The
+	// first override whose substring the message contains applies.
+	MsgLevelOverrides []MsgLevelOverride
 	// AddSource determines whether to add source information to logs.
 	AddSource bool
 	// HandlerType is the type of handler to use.
 	HandlerType string
+	// JSONHTMLEscape determines whether JSON handlers escape the HTML-unsafe
+	// characters <, > and &.
+	JSONHTMLEscape bool
+	// JSONFast determines whether JSON handlers encode the values of the
+	// common kinds with a specialized encoder instead of slog.JSONHandler.
+	JSONFast bool
+	// JSONArray determines whether JSON file outputs write the records as
+	// the elements of a single JSON array, closed by Close.
+	JSONArray bool
+	// GELFHost is the host reported by GELF handlers. Empty means the hostname.
+	GELFHost string
 	// WriterType is the type of writer to use.
 	WriterType string
-	// WriterFilePath is the path to the log file.
+	// Outputs lists handler/writer pairs that each receive every record. If it
+	// is set, HandlerType, WriterType and WriterFilePath are not used.
+	Outputs []Output
+	// AuditMarkerKey, if set, is the key of the attribute that marks records
+	// to be written to AuditOutput as well, when its value is true.
+	AuditMarkerKey string
+	// AuditOutput is the handler/writer pair that receives the marked records.
+	AuditOutput Output
+	// AuditStripMarker determines whether to remove the marker attribute from
+	// the records of the normal output.
+	AuditStripMarker bool
+	// WriterFilePath is the path to the log file. It may contain the date/time
+	// placeholders %Y, %m, %d and %H, expanded when the file is opened.
 	WriterFilePath string
 	// WriterFileNoAppend determines whether to append to the log file.
 	WriterFileNoAppend bool
 	// WriterFilePerm is the permission for the log file.
 	WriterFilePerm os.FileMode
+	// WriterProbe determines whether to perform a test write when the logger is built.
+	WriterProbe bool
+	// WriterFileIndex determines whether to keep an index of byte offsets
+	// per minute next to log files.
+	WriterFileIndex bool
+	// UnixSocketPath is the path to the Unix domain socket if WriterType is "unixsocket".
+	UnixSocketPath string
+	// UnixSocketType is the type of the Unix domain socket: "unix" for a
+	// stream socket or "unixgram" for a datagram socket.
+	UnixSocketType string
+	// AttrAllowlist lists the attribute keys that are logged. If it is not
+	// empty, all other attributes except the built-in ones are dropped.
+	AttrAllowlist []string
+	// HashKeys lists the attribute keys (dotted paths for attributes in
+	// groups) whose string values are replaced by a salted hash. Keys are
+	// matched case-insensitively.
+	HashKeys []string
+	// HashSalt is the secret salt of the attribute value hashes.
+	HashSalt string
+	// HashLength is the number of hex digits kept from each hash.
+	HashLength int
+	// MaskPatterns are the regular expressions whose matches in the message
+	// and in string attribute values are replaced by MaskReplacement.
+	MaskPatterns []*regexp.Regexp
+	// RedactLevel, if set, limits the hash keys and mask patterns to records
+	// at or above this level, so that lower records show the values as they are.
+	RedactLevel slog.Leveler
+	// NilAttrAction is what happens to attributes with a nil value: "keep",
+	// "drop" or "placeholder". Empty means "keep".
+	NilAttrAction string
+	// NilAttrPlaceholder is the value logged for nil values with the
+	// "placeholder" action. Empty means DefaultNilPlaceholder.
+	NilAttrPlaceholder string
+	// EnvAttrs are added to every record. They are read from the environment
+	// variables named by LOGGER_ATTRS_FROM_ENV.
+	EnvAttrs []slog.Attr
+	// DedupKeys is the policy for attributes with duplicate keys: "first" or
+	// "last" keeps only the first or last value, empty keeps all of them. It
+	// also applies to the records passed to context loggers.
+	DedupKeys string
+	// ProtectBuiltins determines whether user attributes with the key of a
+	// built-in attribute are renamed with the ProtectedKeyPrefix prefix.
+	ProtectBuiltins bool
+	// SortAttrs determines whether to sort attributes by key.
+	SortAttrs bool
+	// MaxAttrs is the maximum number of attributes per record, or 0 for no limit.
+	MaxAttrs int
+	// MaxCollectionLen is the maximum number of elements logged of slice,
+	// array and map attribute values, or 0 for no limit.
+	MaxCollectionLen int
+	// RenameKeys maps attribute keys (dotted paths for attributes in groups)
+	// to the keys they are logged under.
+	RenameKeys map[string]string
+	// Framing is the framing applied to each record written ("none" or "length").
+	Framing string
+	// MaxLineBytes is the maximum length of a written record in bytes,
+	// including its newline, or 0 for no limit.
+	MaxLineBytes int
+	// MaxLineAction is what happens to records longer than MaxLineBytes:
+	// "truncate", "drop" or "split".
+	MaxLineAction string
+	// BrokenPipeAction is what happens to records written after the
+	// destination turned out to be a pipe without a reader: "ignore",
+	// "stderr" or "exit". Empty means DefaultBrokenPipeAction.
+	BrokenPipeAction string
+	// WriterBreakerThreshold is the number of consecutive write failures after
+	// which writes are diverted to stderr. Zero disables the circuit breaker.
+	WriterBreakerThreshold int
+	// WriterBreakerCooldown is how long writes stay diverted before the
+	// writer is tried again.
+	WriterBreakerCooldown time.Duration
+	// WriteTimeout is how long handling a record may take before it is
+	// abandoned and dropped, or 0 for no timeout.
+	WriteTimeout time.Duration
 	// NoPanicOnError determines whether to panic on configuration errors.
 	NoPanicOnError bool
+	// Strict determines whether configuration errors of soft severity fail
+	// instead of being degraded gracefully with a warning (PLANKS_STRICT=soft).
+	Strict bool
+	// Timezone is the location record times are converted to, or nil to keep
+	// the local time.
+	Timezone *time.Location
+	// AddCtxCause determines whether to add the error and cancellation cause
+	// of done contexts to logs.
+	AddCtxCause bool
+	// AddGoID determines whether to add the goroutine id to logs.
+	AddGoID bool
+	// AddUptime determines whether to add the process uptime to logs.
+	AddUptime bool
+	// AddModule determines whether to add the package of the logging call to logs.
+	AddModule bool
+	// StacktraceOnce determines whether repeated stack traces are logged as a
+	// reference to their first occurrence.
+	StacktraceOnce bool
+	// SourceKey is the attribute key used for source information.
+	SourceKey string
+	// SourceFlatten determines whether to collapse source information into a
+	// single "file:line" string attribute.
+	SourceFlatten bool
+	// SourceLevel, if set, limits source information to records at or above
+	// this level. Setting it enables source information.
+	SourceLevel slog.Leveler
+
+	// ContextStrict determines whether logging fails with ErrContextLoggerCycle
+	// when context loggers re-enter each other, instead of falling back to the
+	// logger's own handler.
+	ContextStrict bool
+	// SampleAdaptive determines whether records are sampled adaptively to hold
+	// the output rate near SampleTargetRPS.
+	SampleAdaptive bool
+	// SampleTargetRPS is the target output rate of the adaptive sampler in
+	// records per second.
+	SampleTargetRPS float64
+	// PIDFile is the path of a file to write the process id to, or empty.
+	PIDFile string
+	// HeartbeatInterval is the interval at which Init logs a heartbeat record
+	// through the default logger, or 0 for no heartbeat.
+	HeartbeatInterval time.Duration
+
+	// leveler, if set, is used as the minimum level instead of Level.
+	leveler slog.Leveler
+	// encoder, if set, serializes records instead of the json and text handlers.
+	encoder Encoder
+	// audit, if set, is the handler of the audit output, built with the writers.
+	audit slog.Handler
+	// warnings are logged through the logger once it is built.
+	warnings []configWarning
 }
 
 // ReadConfig reads the logger configuration from environment variables.
 func ReadConfig() (*Config, error) {
 	prefix := os.Getenv(EnvPlanksEnvPrefix)
+	return readConfig(func(key string) string {
+		return getEnv(prefix, key)
+	})
+}
+
+// readConfig reads the logger configuration using lookup to resolve the value
+// of each logger-related environment variable name.
+func readConfig(lookup func(key string) string) (*Config, error) {
 	noPanicOnError := os.Getenv(EnvPlanksNoPanicOnError) != ""
+	strict := parseStrict(os.Getenv(EnvPlanksStrict))
 
 	// Only proceed with configuration if at least one logger-related env var is set
-	if !isAnyLoggerEnvVarSet(prefix) {
+	if !isAnyLoggerEnvVarSet(lookup) {
 		return nil, nil
 	}
 
+	lookup, err := optsLookup(lookup)
+	if err != nil {
+		return nil, err
+	}
+	lookup, err = presetLookup(lookup)
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		HandlerType:    DefaultHandlerType,
 		WriterType:     DefaultWriterType,
 		WriterFilePerm: DefaultFilePerm,
 		NoPanicOnError: noPanicOnError,
+		Strict:         strict,
 	}
 
 	// Parse level
-	levelStr := getEnv(prefix, EnvLoggerLevel)
+	levelStr := lookup(EnvLoggerLevel)
 	if levelStr != "" {
 		var level slog.Level
 		if err := level.UnmarshalText([]byte(levelStr)); err != nil {
-			return nil, fmt.Errorf("%w: %w", ErrInvalidLevel, err)
+			fallbackStr := os.Getenv(EnvPlanksLevelFallback)
+			if fallbackStr == "" {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidLevel, err)
+			}
+			if err := level.UnmarshalText([]byte(fallbackStr)); err != nil {
+				return nil, fmt.Errorf("%w: fallback level: %w", ErrInvalidLevel, err)
+			}
+			if err := config.softError(fmt.Errorf("%w: %q", ErrLevelFallback, levelStr), "invalid log level, using the fallback level", "level", levelStr, "fallback", level.String()); err != nil {
+				return nil, err
+			}
 		}
 		config.Level = level
 	}
 
+	// Parse suppressed record notes
+	if everyStr := lookup(EnvLoggerNoteSuppressed); everyStr != "" {
+		every, err := strconv.Atoi(everyStr)
+		if err != nil || every <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidNoteSuppressed, everyStr)
+		}
+		config.NoteSuppressed = every
+	}
+
+	// Parse message level overrides
+	msgLevelOverrides, err := parseMsgLevelOverrides(lookup(EnvLoggerMsgLevelOverrides))
+	if err != nil {
+		return nil, err
+	}
+	config.MsgLevelOverrides = msgLevelOverrides
+
 	// Parse add source
-	config.AddSource = getEnv(prefix, EnvLoggerAddSource) != ""
+	config.AddSource = lookup(EnvLoggerAddSource) != ""
+	if sourceLevelStr := lookup(EnvLoggerSourceLevel); sourceLevelStr != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(sourceLevelStr)); err != nil {
+			return nil, fmt.Errorf("%w: source level: %w", ErrInvalidLevel, err)
+		}
+		config.SourceLevel = level
+	}
 
 	// Parse handler type
-	if handlerType := getEnv(prefix, EnvLoggerHandler); handlerType != "" {
+	if handlerType := lookup(EnvLoggerHandler); handlerType != "" {
 		handlerType = strings.ToLower(handlerType)
 		if !isValidHandlerType(handlerType) {
 			return nil, fmt.Errorf("%w: %v", ErrInvalidHandlerType, handlerType)
 		}
 		config.HandlerType = handlerType
 	}
+	config.JSONHTMLEscape = lookup(EnvLoggerJSONHTMLEscape) != ""
+	config.JSONFast = lookup(EnvLoggerJSONFast) != ""
+	config.GELFHost = lookup(EnvLoggerGELFHost)
 
 	// Parse writer type
-	if writerType := getEnv(prefix, EnvLoggerWriter); writerType != "" {
+	if writerType := lookup(EnvLoggerWriter); writerType != "" {
 		writerType = strings.ToLower(writerType)
 		if !isValidWriterType(writerType) {
 			return nil, fmt.Errorf("%w: %v", ErrInvalidWriterType, writerType)
@@ -194,41 +635,341 @@ func ReadConfig() (*Config, error) {
 		config.WriterType = writerType
 	}
 
+	// Parse add goroutine id
+	config.AddGoID = lookup(EnvLoggerAddGoID) != ""
+
+	// Parse add uptime
+	config.AddUptime = lookup(EnvLoggerAddUptime) != ""
+
+	// Parse add module
+	config.AddModule = lookup(EnvLoggerAddModule) != ""
+
+	// Parse stack trace de-duplication
+	config.StacktraceOnce = lookup(EnvLoggerStackOnce) != ""
+
+	// Parse source attribute settings
+	config.SourceKey = lookup(EnvLoggerSourceKey)
+	config.SourceFlatten = lookup(EnvLoggerSourceFlatten) != ""
+
+	// Parse attribute allowlist
+	config.AttrAllowlist = splitList(lookup(EnvLoggerAttrAllowlist))
+
+	// Parse attribute hashing settings
+	if hashKeys := splitList(lookup(EnvLoggerHashKeys)); len(hashKeys) > 0 {
+		config.HashKeys = hashKeys
+		config.HashSalt = lookup(EnvLoggerHashSalt)
+		config.HashLength = DefaultHashLength
+		if lengthStr := lookup(EnvLoggerHashLength); lengthStr != "" {
+			length, err := strconv.Atoi(lengthStr)
+			if err != nil || length <= 0 || length > maxHashLength {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidHashLength, lengthStr)
+			}
+			config.HashLength = length
+		}
+		if config.HashSalt == "" {
+			if err := config.softError(ErrMissingHashSalt, "hashing attribute values without a salt", "keys", hashKeys); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Parse mask patterns
+	maskPatterns, err := parseMaskPatterns(lookup(EnvLoggerMaskPatterns))
+	if err != nil {
+		return nil, err
+	}
+	config.MaskPatterns = maskPatterns
+	if redactLevelStr := lookup(EnvLoggerRedactUnlessLevel); redactLevelStr != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(redactLevelStr)); err != nil {
+			return nil, fmt.Errorf("%w: redact level: %w", ErrInvalidLevel, err)
+		}
+		config.RedactLevel = level
+	}
+
+	// Parse context strict mode
+	config.ContextStrict = lookup(EnvLoggerContextStrict) != ""
+
+	// Parse timezone
+	if name := lookup(EnvLoggerTimezone); name != "" {
+		if err := loadTimezone(config, name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse adaptive sampling settings
+	if lookup(EnvLoggerSampleAdaptive) != "" {
+		targetStr := lookup(EnvLoggerSampleTarget)
+		target, err := strconv.ParseFloat(targetStr, 64)
+		if err != nil || !(target > 0) || math.IsInf(target, 0) {
+			return nil, fmt.Errorf("%w: target rate %q", ErrInvalidSampling, targetStr)
+		}
+		config.SampleAdaptive = true
+		config.SampleTargetRPS = target
+	}
+
+	// Parse heartbeat interval
+	if intervalStr := lookup(EnvLoggerHeartbeatInterval); intervalStr != "" {
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidHeartbeatInterval, intervalStr)
+		}
+		config.HeartbeatInterval = interval
+	}
+
+	// Parse pid file path
+	config.PIDFile = lookup(EnvLoggerPIDFile)
+
+	// Parse attributes from environment variables
+	envAttrs, err := parseAttrsFromEnv(lookup(EnvLoggerAttrsFromEnv))
+	if err != nil {
+		return nil, err
+	}
+	config.EnvAttrs = envAttrs
+
+	// Parse key de-duplication policy
+	if policy := lookup(EnvLoggerDedupKeys); policy != "" {
+		policy = strings.ToLower(policy)
+		if policy != "first" && policy != "last" {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDedupPolicy, policy)
+		}
+		config.DedupKeys = policy
+	}
+
+	// Parse context cancellation cause
+	config.AddCtxCause = lookup(EnvLoggerCtxCause) != ""
+
+	// Parse built-in key protection
+	config.ProtectBuiltins = lookup(EnvLoggerProtectBuiltins) != ""
+
+	// Parse attribute sorting
+	config.SortAttrs = lookup(EnvLoggerSortAttrs) != ""
+
+	// Parse attribute limit
+	if maxStr := lookup(EnvLoggerMaxAttrs); maxStr != "" {
+		maxAttrs, err := strconv.Atoi(maxStr)
+		if err != nil || maxAttrs <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMaxAttrs, maxStr)
+		}
+		config.MaxAttrs = maxAttrs
+	}
+
+	// Parse collection length limit
+	if maxStr := lookup(EnvLoggerMaxCollectionLen); maxStr != "" {
+		maxLen, err := strconv.Atoi(maxStr)
+		if err != nil || maxLen <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidMaxCollectionLen, maxStr)
+		}
+		config.MaxCollectionLen = maxLen
+	}
+
+	// Parse attribute renames
+	renames, err := parseRenameKeys(lookup(EnvLoggerRenameKeys))
+	if err != nil {
+		return nil, err
+	}
+	config.RenameKeys = renames
+
+	// Parse framing
+	if framing := lookup(EnvLoggerFraming); framing != "" {
+		framing = strings.ToLower(framing)
+		if !isValidFraming(framing) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFraming, framing)
+		}
+		config.Framing = framing
+	}
+
+	// Parse line length limit
+	if maxStr := lookup(EnvLoggerMaxLineBytes); maxStr != "" {
+		maxLine, err := strconv.Atoi(maxStr)
+		if err != nil || maxLine < MinMaxLineBytes {
+			return nil, fmt.Errorf("%w: %q: want an integer of at least %d", ErrInvalidMaxLine, maxStr, MinMaxLineBytes)
+		}
+		config.MaxLineBytes = maxLine
+		config.MaxLineAction = "truncate"
+		if action := strings.ToLower(lookup(EnvLoggerMaxLineAction)); action != "" {
+			if action != "truncate" && action != "drop" && action != "split" {
+				return nil, fmt.Errorf("%w: action %q", ErrInvalidMaxLine, action)
+			}
+			config.MaxLineAction = action
+		}
+	}
+
+	// Parse nil attribute action
+	if action := strings.ToLower(lookup(EnvLoggerNilAttrAction)); action != "" {
+		if action != "keep" && action != "drop" && action != "placeholder" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidNilAttrAction, action)
+		}
+		config.NilAttrAction = action
+		config.NilAttrPlaceholder = lookup(EnvLoggerNilAttrPlaceholder)
+	}
+
+	// Parse broken pipe action
+	if action := strings.ToLower(lookup(EnvLoggerBrokenPipeAction)); action != "" {
+		if action != "ignore" && action != "stderr" && action != "exit" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidBrokenPipeAction, action)
+		}
+		config.BrokenPipeAction = action
+	}
+
+	// Parse writer circuit breaker settings
+	if thresholdStr := lookup(EnvLoggerWriterBreakerThreshold); thresholdStr != "" {
+		threshold, err := strconv.Atoi(thresholdStr)
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("%w: threshold %q", ErrInvalidWriterBreaker, thresholdStr)
+		}
+		config.WriterBreakerThreshold = threshold
+		config.WriterBreakerCooldown = DefaultWriterBreakerCooldown
+	}
+	if cooldownStr := lookup(EnvLoggerWriterBreakerCooldown); cooldownStr != "" {
+		cooldown, err := time.ParseDuration(cooldownStr)
+		if err != nil || cooldown <= 0 {
+			return nil, fmt.Errorf("%w: cooldown %q", ErrInvalidWriterBreaker, cooldownStr)
+		}
+		config.WriterBreakerCooldown = cooldown
+	}
+
+	// Parse write timeout
+	if timeoutStr := lookup(EnvLoggerWriteTimeout); timeoutStr != "" {
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidWriteTimeout, timeoutStr)
+		}
+		config.WriteTimeout = timeout
+	}
+
+	// Parse outputs
+	outputs, err := parseOutputs(lookup(EnvLoggerOutputs))
+	if err != nil {
+		return nil, err
+	}
+	config.Outputs = outputs
+
+	// Parse audit settings
+	config.AuditMarkerKey = lookup(EnvLoggerAuditMarkerKey)
+	if auditOutput := lookup(EnvLoggerAuditOutput); auditOutput != "" || config.AuditMarkerKey != "" {
+		if auditOutput == "" || config.AuditMarkerKey == "" {
+			return nil, fmt.Errorf("%w: %s and %s must be set together", ErrInvalidAudit, EnvLoggerAuditMarkerKey, EnvLoggerAuditOutput)
+		}
+		if config.AuditOutput, err = parseAuditOutput(auditOutput); err != nil {
+			return nil, err
+		}
+		config.AuditStripMarker = lookup(EnvLoggerAuditStripMarker) != ""
+	}
+
 	// Parse file-related settings if writer type is 'file'
 	if config.WriterType == "file" {
-		filePath := getEnv(prefix, EnvLoggerWriterFilePath)
+		filePath := lookup(EnvLoggerWriterFilePath)
 		if filePath == "" {
 			return nil, ErrMissingFilePath
 		}
 		config.WriterFilePath = filePath
-		config.WriterFileNoAppend = getEnv(prefix, EnvLoggerWriterNoAppend) != ""
+	}
+	if config.WriterType == "file" || hasFileOutput(config.Outputs) || config.AuditOutput.WriterType == "file" {
+		config.WriterFileNoAppend = lookup(EnvLoggerWriterNoAppend) != ""
 
-		if permStr := getEnv(prefix, EnvLoggerWriterFilePerm); permStr != "" {
+		if permStr := lookup(EnvLoggerWriterFilePerm); permStr != "" {
 			perm, err := strconv.ParseUint(permStr, 8, 32)
 			if err != nil {
 				return nil, fmt.Errorf("%w: %w", ErrInvalidFilePermission, err)
 			}
 			config.WriterFilePerm = os.FileMode(perm)
 		}
+		config.WriterProbe = lookup(EnvLoggerWriterProbe) != ""
+		config.WriterFileIndex = lookup(EnvLoggerWriterFileIndex) != ""
+	}
+	if config.WriterType == "unixsocket" || hasUnixSocketOutput(config.Outputs) || config.AuditOutput.WriterType == "unixsocket" {
+		config.UnixSocketPath = lookup(EnvLoggerUnixSocketPath)
+		if config.UnixSocketPath == "" {
+			return nil, ErrMissingUnixSocketPath
+		}
+		config.UnixSocketType = DefaultUnixSocketType
+		if socketType := strings.ToLower(lookup(EnvLoggerUnixSocketType)); socketType != "" {
+			if socketType != "unix" && socketType != "unixgram" {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidUnixSocketType, socketType)
+			}
+			config.UnixSocketType = socketType
+		}
+	}
+
+	// Parse JSON array output
+	if lookup(EnvLoggerJSONArray) != "" {
+		if !hasJSONFileOutput(config) || config.Framing == "length" {
+			return nil, ErrInvalidJSONArray
+		}
+		config.JSONArray = true
 	}
 
 	return config, nil
 }
 
+// loggerEnvVars lists the environment variables that configure a logger.
+var loggerEnvVars = []string{
+	EnvLoggerLevel,
+	EnvLoggerAddSource,
+	EnvLoggerHandler,
+	EnvLoggerJSONHTMLEscape,
+	EnvLoggerJSONFast,
+	EnvLoggerGELFHost,
+	EnvLoggerJSONArray,
+	EnvLoggerWriter,
+	EnvLoggerWriterFilePath,
+	EnvLoggerWriterNoAppend,
+	EnvLoggerWriterFilePerm,
+	EnvLoggerAddGoID,
+	EnvLoggerAddUptime,
+	EnvLoggerAddModule,
+	EnvLoggerStackOnce,
+	EnvLoggerSourceKey,
+	EnvLoggerSourceFlatten,
+	EnvLoggerSourceLevel,
+	EnvLoggerWriterProbe,
+	EnvLoggerWriterFileIndex,
+	EnvLoggerUnixSocketPath,
+	EnvLoggerUnixSocketType,
+	EnvLoggerMsgLevelOverrides,
+	EnvLoggerFraming,
+	EnvLoggerAttrAllowlist,
+	EnvLoggerRenameKeys,
+	EnvLoggerPIDFile,
+	EnvLoggerSampleAdaptive,
+	EnvLoggerSampleTarget,
+	EnvLoggerOutputs,
+	EnvLoggerAuditMarkerKey,
+	EnvLoggerAuditOutput,
+	EnvLoggerAuditStripMarker,
+	EnvLoggerMaxAttrs,
+	EnvLoggerMaxCollectionLen,
+	EnvLoggerContextStrict,
+	EnvLoggerPreset,
+	EnvLoggerOpts,
+	EnvLoggerTimezone,
+	EnvLoggerDedupKeys,
+	EnvLoggerAttrsFromEnv,
+	EnvLoggerHeartbeatInterval,
+	EnvLoggerWriteTimeout,
+	EnvLoggerHashKeys,
+	EnvLoggerHashSalt,
+	EnvLoggerHashLength,
+	EnvLoggerMaskPatterns,
+	EnvLoggerRedactUnlessLevel,
+	EnvLoggerSortAttrs,
+	EnvLoggerProtectBuiltins,
+	EnvLoggerCtxCause,
+	EnvLoggerMaxLineBytes,
+	EnvLoggerMaxLineAction,
+	EnvLoggerBrokenPipeAction,
+	EnvLoggerNilAttrAction,
+	EnvLoggerNilAttrPlaceholder,
+	EnvLoggerNoteSuppressed,
+	EnvLoggerWriterBreakerThreshold,
+	EnvLoggerWriterBreakerCooldown,
+}
+
 // isAnyLoggerEnvVarSet checks if any of the logger-related environment variables are set.
-func isAnyLoggerEnvVarSet(prefix string) bool {
-	envVars := []string{
-		EnvLoggerLevel,
-		EnvLoggerAddSource,
-		EnvLoggerHandler,
-		EnvLoggerWriter,
-		EnvLoggerWriterFilePath,
-		EnvLoggerWriterNoAppend,
-		EnvLoggerWriterFilePerm,
-	}
-
-	for _, envVar := range envVars {
-		if getEnv(prefix, envVar) != "" {
+func isAnyLoggerEnvVarSet(lookup func(key string) string) bool {
+	for _, envVar := range loggerEnvVars {
+		if lookup(envVar) != "" {
 			return true
 		}
 	}
@@ -241,6 +982,8 @@ func isValidHandlerType(handlerType string) bool {
 	validTypes := map[string]bool{
 		"json":    true,
 		"text":    true,
+		"bare":    true,
+		"gelf":    true,
 		"discard": true,
 	}
 	return validTypes[handlerType]
@@ -252,10 +995,32 @@ func isValidWriterType(writerType string) bool {
 		"stdout": true,
 		"stderr": true,
 		"file":   true,
+		// Unix domain sockets are only available on Unix platforms.
+		"unixsocket": unixSocketSupported,
 	}
 	return validTypes[writerType]
 }
 
+// isValidFraming checks if the given framing type is valid.
+func isValidFraming(framing string) bool {
+	validTypes := map[string]bool{
+		"none":   true,
+		"length": true,
+	}
+	return validTypes[framing]
+}
+
+// splitList splits a comma-separated list, trimming spaces and skipping empty entries.
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 // getEnv gets an environment variable with the given prefix.
 func getEnv(prefix, key string) string {
 	if prefix != "" {
@@ -264,26 +1029,261 @@ func getEnv(prefix, key string) string {
 	return os.Getenv(key)
 }
 
-// createHandler creates a handler based on the given config.
+// replaceAttr returns the ReplaceAttr function for handlers built from the
+// given config. It chains every attribute rewriter the config enables.
+func replaceAttr(config *Config) func([]string, slog.Attr) slog.Attr {
+	fns := []func([]string, slog.Attr) slog.Attr{
+		replaceRawJSON,
+	}
+	if config.HandlerType == "bare" {
+		fns = append(fns, replaceBare)
+	}
+	// Filters run before the rewriters so that they see the original keys.
+	for _, fn := range []func([]string, slog.Attr) slog.Attr{
+		replaceAllowlist(config),
+		replaceNilAttrs(config),
+		replaceRedaction(config),
+		replaceMaxCollectionLen(config),
+		replaceTimezone(config),
+		replaceAttrTransforms(),
+		replaceSource(config),
+		replaceRenameKeys(config),
+	} {
+		if fn != nil {
+			fns = append(fns, fn)
+		}
+	}
+	return chainReplaceAttr(fns...)
+}
+
+// replaceBare is a ReplaceAttr function that drops the built-in time and level
+// attributes, leaving only the message and attributes for piping into other tools.
+func replaceBare(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+		return slog.Attr{}
+	}
+	return a
+}
+
+// chainReplaceAttr composes ReplaceAttr functions, applying them in order.
+// The chain stops as soon as one of them discards the attribute.
+func chainReplaceAttr(fns ...func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		for _, fn := range fns {
+			a = fn(groups, a)
+			if a.Equal(slog.Attr{}) {
+				return a
+			}
+		}
+		return a
+	}
+}
+
+// createHandler creates a context-aware handler based on the given config.
 func createHandler(config *Config, w io.Writer) slog.Handler {
+	return withContextAwareness(config, createBaseHandler(config, w))
+}
+
+// withContextAwareness wraps the given base handler in a context-aware handler.
+func withContextAwareness(config *Config, handler slog.Handler) slog.Handler {
+	if handler == slog.DiscardHandler {
+		return handler // Discard handler does not log anything, so no need for context awareness
+	}
+	return &contextAwareHandler{
+		internal: handler,
+		strict:   config.ContextStrict,
+		dedup:    config.DedupKeys,
+	}
+}
+
+// buildBaseHandler creates the writers and the handler for the given config
+// without context awareness.
+func buildBaseHandler(config *Config) (slog.Handler, error) {
+	if config.AuditMarkerKey != "" {
+		audit, err := buildAuditHandler(config)
+		if err != nil {
+			return nil, err
+		}
+		config.audit = audit
+	}
+	if len(config.Outputs) > 0 {
+		return buildOutputsHandler(config)
+	}
+	writer, err := buildWriter(config)
+	if err != nil {
+		return nil, err
+	}
+	return createBaseHandler(config, writer), nil
+}
+
+// createBaseHandler creates the handler for the given config without context awareness.
+func createBaseHandler(config *Config, w io.Writer) slog.Handler {
+	handler := createFormatHandler(config, w)
+	if handler == slog.DiscardHandler {
+		return handler
+	}
+	return wrapHandler(config, handler)
+}
+
+// minLevel returns the minimum level to log.
+func (c *Config) minLevel() slog.Leveler {
+	if c.leveler != nil {
+		return c.leveler
+	}
+	return c.Level
+}
+
+// createFormatHandler creates the json, text or discard handler for the given
+// config, without any of the handler wrappers.
+func createFormatHandler(config *Config, w io.Writer) slog.Handler {
 	opts := &slog.HandlerOptions{
-		Level:     config.Level,
-		AddSource: config.AddSource,
+		Level:       config.minLevel(),
+		AddSource:   config.AddSource || config.SourceLevel != nil,
+		ReplaceAttr: replaceAttr(config),
 	}
 
+	if config.encoder != nil && config.HandlerType != "discard" {
+		return newEncoderHandler(config.encoder, w, opts)
+	}
 	switch config.HandlerType {
 	case "json":
-		return newContextAwareHandler(slog.NewJSONHandler(w, opts))
-	case "text":
-		return newContextAwareHandler(slog.NewTextHandler(w, opts))
+		if config.JSONHTMLEscape {
+			w = newHTMLEscapeWriter(w)
+		}
+		if config.JSONFast {
+			return newFastJSONHandler(w, opts)
+		}
+		return slog.NewJSONHandler(w, opts)
+	case "text", "bare":
+		return slog.NewTextHandler(w, opts)
+	case "gelf":
+		return newEncoderHandler(newGELFEncoder(config.GELFHost, opts.AddSource), w, opts)
 	case "discard":
-		return slog.DiscardHandler // Discard handler does not log anything, so no need for context awareness
+		return slog.DiscardHandler
 	default:
 		// This should never happen due to validation in ReadConfig
-		return newContextAwareHandler(slog.NewTextHandler(w, opts))
+		return slog.NewTextHandler(w, opts)
 	}
 }
 
+// wrapHandler wraps the given handler with the handler wrappers enabled by the config.
+func wrapHandler(config *Config, handler slog.Handler) slog.Handler {
+	// The context output is innermost so that it receives the same records
+	// as the handler itself.
+	handler = newOutputHandler(handler, config)
+	// The audit copy is taken right outside the context output so that it
+	// receives the records as processed by the other wrappers.
+	if config.audit != nil {
+		handler = newAuditHandler(handler, config.audit, config.AuditMarkerKey, config.AuditStripMarker)
+	}
+	// Level-dependent redaction is taken outside the audit copy so that the
+	// audit output is redacted as well.
+	if config.RedactLevel != nil {
+		if replace := redaction(config); replace != nil {
+			handler = newRedactLevelHandler(handler, config.RedactLevel, replace)
+		}
+	}
+	if config.SourceLevel != nil {
+		handler = newSourceLevelHandler(handler, config.SourceLevel)
+	}
+	if config.ProtectBuiltins {
+		handler = newProtectHandler(handler)
+	}
+	// The attribute limit comes next so that it also counts the attributes
+	// added by the other wrappers.
+	if config.MaxAttrs > 0 {
+		handler = newMaxAttrsHandler(handler, config.MaxAttrs)
+	}
+	// Sorting runs before the attribute limit so that the attributes kept
+	// do not depend on the order of the logging calls.
+	if config.SortAttrs {
+		handler = newSortHandler(handler)
+	}
+	// De-duplication runs before the attribute limit so that only the
+	// remaining attributes are counted.
+	if config.DedupKeys != "" {
+		handler = newDedupHandler(handler, config.DedupKeys)
+	}
+	if config.AddGoID {
+		handler = newGoIDHandler(handler)
+	}
+	if config.AddUptime {
+		handler = newUptimeHandler(handler)
+	}
+	if config.AddModule {
+		handler = newModuleHandler(handler)
+	}
+	if config.AddCtxCause {
+		handler = newCauseHandler(handler)
+	}
+	if config.StacktraceOnce {
+		handler = newStackOnceHandler(handler)
+	}
+	if fns := registeredDerivedAttrs(); len(fns) > 0 {
+		handler = newDerivedHandler(handler, fns)
+	}
+	// The timeout goes right inside the sampler so that the records dropped
+	// by the sampler do not cost a goroutine.
+	if config.WriteTimeout > 0 {
+		handler = newTimeoutHandler(handler, config.WriteTimeout)
+	}
+	if config.SampleAdaptive {
+		handler = newSamplerHandler(handler, newAdaptiveSampler(config.SampleTargetRPS))
+	}
+	// Events are matched before sampling so that they do not depend on the
+	// sample rate.
+	if matchers := registeredEventMatchers(); len(matchers) > 0 {
+		handler = newEventHandler(handler, matchers)
+	}
+	// Suppressed records are counted outermost, so that they cost no work in
+	// the other wrappers.
+	if config.NoteSuppressed > 0 {
+		handler = newSuppressHandler(handler, config.minLevel(), config.NoteSuppressed)
+	}
+	// Message level overrides come before level filtering so that the
+	// records they demote are filtered, and noted, at their new level.
+	if len(config.MsgLevelOverrides) > 0 {
+		handler = newMsgLevelHandler(handler, config.MsgLevelOverrides)
+	}
+	if attrs := append(buildInfoAttrs(), config.EnvAttrs...); len(attrs) > 0 {
+		handler = handler.WithAttrs(attrs)
+	}
+	return handler
+}
+
+// wrapWriter wraps the given writer with the writer wrappers enabled by the config.
+func wrapWriter(config *Config, w io.Writer) io.Writer {
+	// Broken pipes are handled innermost so that the other wrappers see
+	// them as successful writes rather than as failures to retry or count.
+	// SIGPIPE is only handled when an action is set explicitly, so that by
+	// default a broken stdout or stderr still ends the process as usual.
+	if (config.BrokenPipeAction == "ignore" || config.BrokenPipeAction == "stderr") && (w == os.Stdout || w == os.Stderr) {
+		ignoreSIGPIPE()
+	}
+	var fallback io.Writer = os.Stderr
+	if w == os.Stderr {
+		fallback = nil
+	}
+	w = newBrokenPipeWriter(w, fallback, config.BrokenPipeAction)
+	if config.JSONArray && config.HandlerType == "json" && config.WriterType == "file" {
+		aw := newJSONArrayWriter(w)
+		registerCloser(closeFlush, aw.Close)
+		w = aw
+	}
+	if config.Framing == "length" {
+		w = newLengthFrameWriter(w)
+	}
+	if config.WriterBreakerThreshold > 0 {
+		w = newBreakerWriter(w, os.Stderr, config.WriterBreakerThreshold, config.WriterBreakerCooldown)
+	}
+	// The line length limit is outermost so that it sees the records as
+	// encoded by the handler.
+	if config.MaxLineBytes > 0 {
+		w = newMaxLineWriter(w, config.MaxLineBytes, config.MaxLineAction)
+	}
+	return w
+}
+
 // createWriter creates a writer based on the given config.
 func createWriter(config *Config) (io.Writer, error) {
 	switch config.WriterType {
@@ -292,19 +1292,26 @@ func createWriter(config *Config) (io.Writer, error) {
 	case "stderr":
 		return os.Stderr, nil
 	case "file":
-		flag := os.O_CREATE | os.O_WRONLY
-		if !config.WriterFileNoAppend {
-			flag |= os.O_APPEND
-		} else {
-			flag |= os.O_TRUNC
-		}
-		return os.OpenFile(config.WriterFilePath, flag, config.WriterFilePerm)
+		return os.OpenFile(expandFilePath(config.WriterFilePath, time.Now()), fileOpenFlag(config), config.WriterFilePerm)
+	case "unixsocket":
+		return newUnixSocketWriter(config.UnixSocketType, config.UnixSocketPath)
 	default:
 		// This should never happen due to validation in ReadConfig
 		return os.Stderr, nil
 	}
 }
 
+// fileOpenFlag returns the flags to open the log files of the given config with.
+func fileOpenFlag(config *Config) int {
+	flag := os.O_CREATE | os.O_WRONLY
+	if !config.WriterFileNoAppend {
+		flag |= os.O_APPEND
+	} else {
+		flag |= os.O_TRUNC
+	}
+	return flag
+}
+
 // Build creates a logger based on environment variables.
 // If no relevant environment variables are set, it returns (nil, ErrNoEnvVarSet).
 // If an error occurs during configuration, it returns (nil, error).
@@ -313,36 +1320,164 @@ func Build() (*slog.Logger, error) {
 	if err != nil {
 		return nil, err
 	}
+	return buildLogger(config)
+}
+
+// BuildOrDefault creates a logger based on environment variables like Build.
+// If no relevant environment variables are set, it returns a context-aware
+// logger with the default settings (text records at info level and above on
+// stderr) instead of an error, so that context loggers work without any
+// configuration.
+// If an error occurs during configuration, it will either panic (by default)
+// or, if PLANKS_NO_PANIC_ON_ERROR is set, log the error through the default
+// settings logger and return that logger.
+func BuildOrDefault() *slog.Logger {
+	logger, err := Build()
+	if err == nil {
+		return logger
+	}
+	if !errors.Is(err, ErrNoEnvVarSet) && os.Getenv(EnvPlanksNoPanicOnError) == "" {
+		panic(err)
+	}
+
+	logger, defaultErr := buildLogger(&Config{
+		HandlerType:    DefaultHandlerType,
+		WriterType:     DefaultWriterType,
+		WriterFilePerm: DefaultFilePerm,
+	})
+	if defaultErr != nil {
+		// This should never happen, as the default settings open no files
+		panic(defaultErr)
+	}
+	if !errors.Is(err, ErrNoEnvVarSet) {
+		logger.Error("failed to configure logger, using the default settings", "error", err)
+	}
+	return logger
+}
+
+// BuildWithLeveler creates a logger based on environment variables like Build,
+// but uses the given leveler as the minimum level instead of LOGGER_LEVEL,
+// which is ignored. Changes to a dynamic leveler such as a *slog.LevelVar take
+// effect immediately.
+// If no relevant environment variables other than LOGGER_LEVEL are set, it
+// returns (nil, ErrNoEnvVarSet).
+func BuildWithLeveler(leveler slog.Leveler) (*slog.Logger, error) {
+	prefix := os.Getenv(EnvPlanksEnvPrefix)
+	config, err := readConfig(func(key string) string {
+		if key == EnvLoggerLevel {
+			return ""
+		}
+		return getEnv(prefix, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		config.leveler = leveler
+	}
+	return buildLogger(config)
+}
 
+// buildLogger creates a logger from the given config.
+// A nil config means no relevant environment variables were set.
+func buildLogger(config *Config) (*slog.Logger, error) {
 	// If no configuration is provided, return nil
 	if config == nil {
 		return nil, ErrNoEnvVarSet
 	}
 
+	handler, err := buildBaseHandler(config)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := slog.New(withContextAwareness(config, handler))
+	emitWarnings(config, logger)
+	setupPIDFile(config, logger)
+	return logger, nil
+}
+
+// buildWriter creates the writer for the given config, probing and wrapping it as configured.
+func buildWriter(config *Config) (io.Writer, error) {
 	writer, err := createWriter(config)
 	if err != nil {
 		return nil, err
 	}
 
-	handler := createHandler(config, writer)
+	if config.WriterProbe {
+		if err := probeWriter(config, writer); err != nil {
+			if closer, ok := writer.(io.Closer); ok {
+				closer.Close()
+			}
+			return nil, err
+		}
+	}
+	if sw, ok := writer.(*unixSocketWriter); ok {
+		registerCloser(closeWriters, sw.Close)
+	}
+	if f, ok := writer.(*os.File); ok && config.WriterType == "file" {
+		registerCloser(closeWriters, f.Close)
+		if config.WriterFileIndex {
+			iw, err := newFileIndexWriter(f, fileOpenFlag(config), config.WriterFilePerm)
+			if err != nil {
+				return nil, err
+			}
+			registerCloser(closeWriters, iw.Close)
+			writer = iw
+		}
+	}
 
-	return slog.New(handler), nil
+	return wrapWriter(config, writer), nil
 }
 
 // Init creates a logger based on environment variables and sets it as the default logger.
 // If no relevant environment variables are set, it does nothing.
 // If an error occurs during configuration, it will either panic (by default) or log the error
 // and continue without changing the default logger (if PLANKS_NO_PANIC_ON_ERROR is set).
+//
+// Calling Init again reconfigures the default logger it installed in place,
+// so loggers derived from it with With or WithGroup pick up the new configuration.
 func Init() {
-	logger, err := Build()
-	if err != nil && !errors.Is(err, ErrNoEnvVarSet) {
+	if err := initDefault(); err != nil {
 		if os.Getenv(EnvPlanksNoPanicOnError) != "" {
 			return
 		}
 		panic(err)
 	}
+}
+
+// initDefault configures the default logger from environment variables.
+// If the current default logger was installed by a previous call, its handler
+// is swapped instead of installing a new default logger.
+// Records buffered by CaptureEarly are replayed once it is done.
+func initDefault() error {
+	defer releaseEarly()
+
+	config, err := ReadConfig()
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return nil
+	}
+
+	// The default logger's level is dynamic so that SetLevelProvider can
+	// change it. It is set once the handler has been built, so that a failed
+	// Init leaves the level of the current default logger unchanged.
+	config.leveler = &defaultLevel
+	handler, err := buildBaseHandler(config)
+	if err != nil {
+		return err
+	}
+	defaultLevel.Set(config.Level)
 
-	if logger != nil {
-		slog.SetDefault(logger)
+	logger := slog.New(handler)
+	emitWarnings(config, logger)
+	setupPIDFile(config, logger)
+	if !swapDefaultHandler(handler, config.ContextStrict, config.DedupKeys) {
+		installDefault(handler, config.ContextStrict, config.DedupKeys)
 	}
+	setHeartbeat(config.HeartbeatInterval)
+	currentConfig.Store(config)
+	return nil
 }
@@ -1,12 +1,20 @@
 package slog
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestReadConfig(t *testing.T) {
@@ -91,6 +99,19 @@ func TestReadConfig(t *testing.T) {
 				NoPanicOnError:     false,
 			},
 		},
+		{
+			name: "File Writer With Percent In Path",
+			envVars: map[string]string{
+				EnvLoggerWriter:         "file",
+				EnvLoggerWriterFilePath: "/tmp/100%-%Q.log",
+			},
+			expected: &Config{
+				HandlerType:    DefaultHandlerType,
+				WriterType:     "file",
+				WriterFilePath: "/tmp/100%-%Q.log",
+				WriterFilePerm: DefaultFilePerm,
+			},
+		},
 		{
 			name: "File Writer With Invalid Permission",
 			envVars: map[string]string{
@@ -116,6 +137,30 @@ func TestReadConfig(t *testing.T) {
 				NoPanicOnError: true,
 			},
 		},
+		{
+			name: "With Goroutine ID",
+			envVars: map[string]string{
+				EnvLoggerAddGoID: "true",
+			},
+			expected: &Config{
+				HandlerType:    DefaultHandlerType,
+				WriterType:     DefaultWriterType,
+				WriterFilePerm: DefaultFilePerm,
+				AddGoID:        true,
+			},
+		},
+		{
+			name: "With Context Strict Mode",
+			envVars: map[string]string{
+				EnvLoggerContextStrict: "true",
+			},
+			expected: &Config{
+				HandlerType:    DefaultHandlerType,
+				WriterType:     DefaultWriterType,
+				WriterFilePerm: DefaultFilePerm,
+				ContextStrict:  true,
+			},
+		},
 		{
 			name: "With Prefix",
 			envVars: map[string]string{
@@ -202,6 +247,9 @@ func TestReadConfig(t *testing.T) {
 			if config.NoPanicOnError != tt.expected.NoPanicOnError {
 				t.Errorf("NoPanicOnError: expected %v, got %v", tt.expected.NoPanicOnError, config.NoPanicOnError)
 			}
+			if config.AddGoID != tt.expected.AddGoID {
+				t.Errorf("AddGoID: expected %v, got %v", tt.expected.AddGoID, config.AddGoID)
+			}
 		})
 	}
 }
@@ -226,6 +274,13 @@ func TestCreateHandler(t *testing.T) {
 		t.Errorf("createHandler returned nil for Text handler")
 	}
 
+	// Test Bare handler
+	config.HandlerType = "bare"
+	bareHandler := createHandler(config, os.Stderr)
+	if bareHandler == nil {
+		t.Errorf("createHandler returned nil for Bare handler")
+	}
+
 	// Test Discard handler
 	config.HandlerType = "discard"
 	discardHandler := createHandler(config, os.Stderr)
@@ -234,6 +289,17 @@ func TestCreateHandler(t *testing.T) {
 	}
 }
 
+func TestBareHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare"}, &buf))
+
+	logger.Info("hello", "k", "v", slog.Group("g", "level", 1))
+
+	if expected := "msg=hello k=v g.level=1\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
 func TestBuild(t *testing.T) {
 	// Save original environment variables
 	origEnvs := saveEnvVars()
@@ -271,6 +337,132 @@ func TestBuild(t *testing.T) {
 	}
 }
 
+func TestReadConfigLevelFallback(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	// Without a fallback, a typo fails
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerLevel: "information"}); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("expected ErrInvalidLevel, got %v", err)
+	}
+
+	// With a fallback, the fallback level is used and a warning is logged
+	path := filepath.Join(t.TempDir(), "test.log")
+	config, err := readConfigFromEnv(t, map[string]string{
+		EnvLoggerLevel:          "information",
+		EnvPlanksLevelFallback:  "warn",
+		EnvLoggerWriter:         "file",
+		EnvLoggerWriterFilePath: path,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Level != slog.LevelWarn {
+		t.Errorf("expected the fallback level WARN, got %v", config.Level)
+	}
+	if _, err := buildLogger(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer Close()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), `msg="invalid log level, using the fallback level" level=information fallback=WARN`) {
+		t.Errorf("expected a fallback warning, got %q", data)
+	}
+
+	// A valid level ignores the fallback
+	config, err = readConfigFromEnv(t, map[string]string{EnvLoggerLevel: "debug", EnvPlanksLevelFallback: "warn"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Level != slog.LevelDebug || len(config.warnings) != 0 {
+		t.Errorf("expected DEBUG without warnings, got %v %v", config.Level, config.warnings)
+	}
+
+	// An invalid fallback fails
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerLevel: "information", EnvPlanksLevelFallback: "loud"}); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("expected ErrInvalidLevel, got %v", err)
+	}
+
+	// The fallback is a soft error, which PLANKS_STRICT=soft fails
+	_, err = readConfigFromEnv(t, map[string]string{EnvLoggerLevel: "information", EnvPlanksLevelFallback: "warn", EnvPlanksStrict: "soft"})
+	if !errors.Is(err, ErrLevelFallback) || ErrorSeverity(err) != SeveritySoft {
+		t.Errorf("expected a soft ErrLevelFallback, got %v", err)
+	}
+}
+
+func TestBuildWithLeveler(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	// LOGGER_LEVEL is ignored, even when invalid
+	clearEnvVars()
+	os.Setenv(EnvLoggerLevel, "invalid")
+	var level slog.LevelVar
+	if _, err := BuildWithLeveler(&level); !errors.Is(err, ErrNoEnvVarSet) {
+		t.Errorf("expected ErrNoEnvVarSet, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "test.log")
+	os.Setenv(EnvLoggerWriter, "file")
+	os.Setenv(EnvLoggerWriterFilePath, path)
+	logger, err := BuildWithLeveler(&level)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := logger.Handler().(*contextAwareHandler); !ok {
+		t.Errorf("expected a contextAwareHandler, got %T", logger.Handler())
+	}
+
+	logger.Debug("hidden")
+	level.Set(slog.LevelDebug)
+	logger.Debug("shown")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if strings.Contains(string(data), "hidden") || !strings.Contains(string(data), "shown") {
+		t.Errorf("expected only the record logged after lowering the level, got %q", data)
+	}
+}
+
+func TestBuildOrDefault(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	// Without configuration, a context-aware default logger is returned
+	clearEnvVars()
+	logger := BuildOrDefault()
+	if _, ok := logger.Handler().(*contextAwareHandler); !ok {
+		t.Fatalf("expected a contextAwareHandler, got %T", logger.Handler())
+	}
+	if logger.Enabled(context.Background(), slog.LevelDebug) || !logger.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("expected the default logger to log at info level and above")
+	}
+
+	testHandler := newTestBufferHandler()
+	ctx := WithContext(context.Background(), slog.New(testHandler))
+	logger.InfoContext(ctx, "via context")
+	if len(testHandler.logs) != 1 || testHandler.logs[0] != "INFO: via context []" {
+		t.Errorf("expected the record to reach the context logger, got %q", testHandler.logs)
+	}
+
+	// Configuration errors panic by default
+	os.Setenv(EnvLoggerLevel, "invalid")
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected a panic with an invalid config")
+		}
+	}()
+	BuildOrDefault()
+}
+
 func TestCreateWriter(t *testing.T) {
 	// Test stdout writer
 	config := &Config{
@@ -331,44 +523,36 @@ func TestCreateWriter(t *testing.T) {
 }
 
 // Helper functions for managing environment variables in tests
-func saveEnvVars() map[string]string {
-	envVars := []string{
-		EnvLoggerLevel,
-		EnvLoggerAddSource,
-		EnvLoggerHandler,
-		EnvLoggerWriter,
-		EnvLoggerWriterFilePath,
-		EnvLoggerWriterNoAppend,
-		EnvLoggerWriterFilePerm,
-		EnvPlanksNoPanicOnError,
-		EnvPlanksEnvPrefix,
-	}
+func testEnvVars() []string {
+	envVars := append([]string{}, loggerEnvVars...)
+	return append(envVars, EnvPlanksNoPanicOnError, EnvPlanksEnvPrefix, EnvPlanksStrict, EnvPlanksLevelFallback)
+}
 
+func saveEnvVars() map[string]string {
 	saved := make(map[string]string)
-	for _, env := range envVars {
+	for _, env := range testEnvVars() {
 		saved[env] = os.Getenv(env)
 	}
 	return saved
 }
 
 func clearEnvVars() {
-	envVars := []string{
-		EnvLoggerLevel,
-		EnvLoggerAddSource,
-		EnvLoggerHandler,
-		EnvLoggerWriter,
-		EnvLoggerWriterFilePath,
-		EnvLoggerWriterNoAppend,
-		EnvLoggerWriterFilePerm,
-		EnvPlanksNoPanicOnError,
-		EnvPlanksEnvPrefix,
-	}
-
-	for _, env := range envVars {
+	for _, env := range testEnvVars() {
 		os.Unsetenv(env)
 	}
 }
 
+// readConfigFromEnv clears the logger environment variables, sets envVars for
+// the duration of the test and reads the resulting configuration.
+func readConfigFromEnv(t *testing.T, envVars map[string]string) (*Config, error) {
+	t.Helper()
+	clearEnvVars()
+	for k, v := range envVars {
+		t.Setenv(k, v)
+	}
+	return ReadConfig()
+}
+
 func restoreEnvVars(saved map[string]string) {
 	for k, v := range saved {
 		if v == "" {
@@ -414,6 +598,30 @@ func (h *testBufferHandler) WithGroup(name string) slog.Handler {
 	return h
 }
 
+// attrBufferHandler is a mock handler like testBufferHandler that also keeps
+// the attributes added with WithAttrs. Derived handlers share the log buffer.
+type attrBufferHandler struct {
+	*testBufferHandler
+	attrs []slog.Attr
+}
+
+func newAttrBufferHandler() *attrBufferHandler {
+	return &attrBufferHandler{testBufferHandler: newTestBufferHandler()}
+}
+
+func (h *attrBufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(h.attrs...)
+	return h.testBufferHandler.Handle(ctx, r)
+}
+
+func (h *attrBufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &attrBufferHandler{
+		testBufferHandler: h.testBufferHandler,
+		attrs:             append(append([]slog.Attr{}, h.attrs...), attrs...),
+	}
+}
+
 func TestContextAwareHandler(t *testing.T) {
 	// Create an internal handler that we'll use as the base handler
 	internalHandler := newTestBufferHandler()
@@ -552,3 +760,238 @@ func TestFromContext(t *testing.T) {
 		t.Errorf("expected retrieved logger to be the default logger")
 	}
 }
+
+// TestContextAwareHandlerSource verifies that source information reports the
+// caller's position even when the record is delegated to a context logger.
+// wrapperHandler passes every call on to next, like a user-defined wrapper.
+type wrapperHandler struct {
+	next slog.Handler
+}
+
+func (h *wrapperHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *wrapperHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(ctx, r)
+}
+
+func (h *wrapperHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &wrapperHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *wrapperHandler) WithGroup(name string) slog.Handler {
+	return &wrapperHandler{next: h.next.WithGroup(name)}
+}
+
+func TestContextAwareHandlerSelfReference(t *testing.T) {
+	internal := newTestBufferHandler()
+	logger := slog.New(newStrictContextAwareHandler(internal))
+	ctx := WithContext(context.Background(), logger)
+
+	// Storing the logger itself in the context is a no-op
+	if err := logger.Handler().Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "self", 0)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// So is storing a logger derived from it
+	derived := logger.With("k", "v")
+	ctx = WithContext(ctx, derived)
+	if err := logger.Handler().Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "derived", 0)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if len(internal.logs) != 2 {
+		t.Errorf("expected 2 logs, got %v", internal.logs)
+	}
+}
+
+func TestContextAwareHandlerWrapped(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		t.Run(fmt.Sprintf("strict=%v", strict), func(t *testing.T) {
+			internal := newTestBufferHandler()
+			handler := newContextAwareHandler(internal)
+			if strict {
+				handler = newStrictContextAwareHandler(internal)
+			}
+
+			// A context logger wrapping the handler leads back to it, which
+			// is not a cycle: the record is logged by the internal handler
+			ctx := WithContext(context.Background(), slog.New(&wrapperHandler{next: handler}))
+
+			if !handler.Enabled(ctx, slog.LevelInfo) {
+				t.Errorf("expected Enabled to fall back to the internal handler")
+			}
+			if err := handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "wrapped", 0)); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if len(internal.logs) != 1 {
+				t.Errorf("expected the record to be logged once, got %v", internal.logs)
+			}
+		})
+	}
+}
+
+// switchHandler stores the logger returned by logger in the context before
+// passing records on to next.
+type switchHandler struct {
+	wrapperHandler
+	logger func() *slog.Logger
+}
+
+func (h *switchHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(WithContext(ctx, h.logger()), level)
+}
+
+func (h *switchHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.next.Handle(WithContext(ctx, h.logger()), r)
+}
+
+func TestContextAwareHandlerCycle(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		t.Run(fmt.Sprintf("strict=%v", strict), func(t *testing.T) {
+			internal := newTestBufferHandler()
+			handler := newContextAwareHandler(internal)
+			if strict {
+				handler = newStrictContextAwareHandler(internal)
+			}
+
+			// Each context logger stores the other one in the context, so
+			// delegation would alternate between them forever
+			var first, second *slog.Logger
+			first = slog.New(&switchHandler{wrapperHandler{next: handler}, func() *slog.Logger { return second }})
+			second = slog.New(&switchHandler{wrapperHandler{next: handler}, func() *slog.Logger { return first }})
+			ctx := WithContext(context.Background(), first)
+
+			if !handler.Enabled(ctx, slog.LevelInfo) {
+				t.Errorf("expected Enabled to fall back to the internal handler")
+			}
+			err := handler.Handle(ctx, slog.NewRecord(time.Now(), slog.LevelInfo, "cycle", 0))
+			if strict {
+				if !errors.Is(err, ErrContextLoggerCycle) {
+					t.Errorf("expected ErrContextLoggerCycle, got %v", err)
+				}
+				if len(internal.logs) != 0 {
+					t.Errorf("expected no logs, got %v", internal.logs)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if len(internal.logs) != 1 {
+				t.Errorf("expected the record to be logged once, got %v", internal.logs)
+			}
+		})
+	}
+}
+
+func TestContextStrictHelpers(t *testing.T) {
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	levelHeader := func(ctx context.Context) context.Context {
+		var requestCtx context.Context
+		handler := NewHTTPMiddleware(WithLevelHeader("X-Log-Level", slog.LevelDebug))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestCtx = r.Context()
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/items", nil).WithContext(ctx)
+		req.Header.Set("X-Log-Level", "debug")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return requestCtx
+	}
+	span := func(ctx context.Context) context.Context {
+		ctx, _ = StartSpan(ctx, "db")
+		return ctx
+	}
+
+	tests := []struct {
+		name     string
+		apply    func(ctx context.Context) context.Context
+		level    slog.Level
+		expected string
+	}{
+		{name: "WithError", apply: func(ctx context.Context) context.Context { return WithError(ctx, errors.New("boom")) }, expected: "error=boom"},
+		{name: "Tags", apply: func(ctx context.Context) context.Context { return Tags(ctx, "billing") }, expected: "tags=[billing]"},
+		{name: "WithMiddleware", apply: func(ctx context.Context) context.Context {
+			return WithMiddleware(ctx, func(next slog.Handler) slog.Handler {
+				return next.WithAttrs([]slog.Attr{slog.Int("extra", 1)})
+			})
+		}, expected: "extra=1"},
+		{name: "WithLevel", apply: func(ctx context.Context) context.Context { return WithLevel(ctx, slog.LevelDebug) }, level: slog.LevelDebug, expected: "level=DEBUG"},
+		{name: "WithLevelHeader", apply: levelHeader, level: slog.LevelDebug, expected: "path=/items"},
+		{name: "StartSpan", apply: span, expected: "db.k=v"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.SetDefault(slog.New(createHandler(&Config{HandlerType: "text", ContextStrict: true}, &buf)))
+			ctx := tt.apply(context.Background())
+
+			// Log through the default logger, which delegates to the
+			// context logger that wraps its handler
+			handler := slog.Default().Handler()
+			if !handler.Enabled(ctx, tt.level) {
+				t.Fatalf("expected level %v to be enabled", tt.level)
+			}
+			r := slog.NewRecord(time.Now(), tt.level, "handled", 0)
+			r.AddAttrs(slog.String("k", "v"))
+			if err := handler.Handle(ctx, r); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Count(buf.String(), "msg=handled") != 1 || strings.Count(buf.String(), tt.expected) != 1 {
+				t.Errorf("expected one record with %s, got %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestContextAwareHandlerSource(t *testing.T) {
+	var defaultBuf, contextBuf bytes.Buffer
+	opts := &slog.HandlerOptions{AddSource: true}
+	defaultLogger := slog.New(newContextAwareHandler(slog.NewJSONHandler(&defaultBuf, opts)))
+
+	tests := []struct {
+		name          string
+		contextLogger *slog.Logger
+	}{
+		{
+			name:          "Plain Context Logger",
+			contextLogger: slog.New(slog.NewJSONHandler(&contextBuf, opts)),
+		},
+		{
+			name:          "Context Logger With Attrs And Group",
+			contextLogger: slog.New(slog.NewJSONHandler(&contextBuf, opts)).With("RequestID", "req-1").WithGroup("g"),
+		},
+		{
+			name:          "Context-Aware Context Logger",
+			contextLogger: slog.New(newContextAwareHandler(slog.NewJSONHandler(&contextBuf, opts))).With("RequestID", "req-1"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaultBuf.Reset()
+			contextBuf.Reset()
+			ctx := context.WithValue(context.Background(), ContextLoggerKey{}, tt.contextLogger)
+
+			_, file, line, _ := runtime.Caller(0)
+			defaultLogger.InfoContext(ctx, "test message") // must stay on the line after runtime.Caller
+			line++
+
+			if defaultBuf.Len() != 0 {
+				t.Errorf("expected no output from default handler, got %q", defaultBuf.String())
+			}
+
+			var record struct {
+				Source slog.Source `json:"source"`
+			}
+			if err := json.Unmarshal(contextBuf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to parse output %q: %v", contextBuf.String(), err)
+			}
+			if record.Source.File != file || record.Source.Line != line {
+				t.Errorf("source: expected %s:%d, got %s:%d", file, line, record.Source.File, record.Source.Line)
+			}
+		})
+	}
+}
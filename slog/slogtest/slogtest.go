@@ -0,0 +1,78 @@
+// Package slogtest provides a logger that writes to the log of a test, so
+// that log output is shown with the test that produced it:
+//
+//	func TestServer(t *testing.T) {
+//		srv := NewServer(slogtest.NewTestLogger(t))
+//		...
+//	}
+//
+// The output is shown for failing tests and with go test -v.
+package slogtest
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	planks_slog "github.com/nakat-t/planks-go/slog"
+)
+
+// NewTestLogger returns a logger whose handler logs each record to tb with
+// tb.Log, formatted compactly as text without the time. Records below debug
+// level are dropped, unless LOGGER_LEVEL (honoring PLANKS_ENV_PREFIX) sets
+// another level; an invalid level fails the test. Records logged after the
+// test has completed are dropped, since tb.Log must not be called then.
+func NewTestLogger(tb testing.TB) *slog.Logger {
+	tb.Helper()
+
+	level := slog.LevelDebug
+	key := planks_slog.EnvLoggerLevel
+	if prefix := os.Getenv(planks_slog.EnvPlanksEnvPrefix); prefix != "" {
+		key = prefix + "_" + key
+	}
+	if s := os.Getenv(key); s != "" {
+		if err := level.UnmarshalText([]byte(s)); err != nil {
+			tb.Fatalf("slogtest: %s: %v", key, err)
+		}
+	}
+
+	w := &tbWriter{tb: tb}
+	tb.Cleanup(w.close)
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+// tbWriter passes each record written by a text handler to tb.Log.
+type tbWriter struct {
+	tb testing.TB
+
+	mu   sync.Mutex
+	done bool
+}
+
+// Write implements io.Writer. The text handler writes each record, ending in
+// a newline, with a single call.
+func (w *tbWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.done {
+		w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+// close stops the forwarding of records to tb once the test has completed.
+func (w *tbWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.done = true
+}
@@ -0,0 +1,78 @@
+package slogtest
+
+import (
+	"fmt"
+	"testing"
+
+	planks_slog "github.com/nakat-t/planks-go/slog"
+)
+
+// fakeTB records the calls a logger makes to a testing.TB.
+type fakeTB struct {
+	testing.TB
+	logs     []string
+	fatals   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Log(args ...any) {
+	tb.logs = append(tb.logs, fmt.Sprint(args...))
+}
+
+func (tb *fakeTB) Fatalf(format string, args ...any) {
+	tb.fatals = append(tb.fatals, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(fn func()) {
+	tb.cleanups = append(tb.cleanups, fn)
+}
+
+func (tb *fakeTB) finish() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}
+
+func TestNewTestLogger(t *testing.T) {
+	t.Setenv(planks_slog.EnvPlanksEnvPrefix, "")
+	t.Setenv(planks_slog.EnvLoggerLevel, "")
+
+	tb := &fakeTB{}
+	logger := NewTestLogger(tb)
+	logger.Debug("debug")
+	logger.With("id", 7).WithGroup("req").Info("served", "path", "/")
+
+	expected := []string{"level=DEBUG msg=debug", "level=INFO msg=served id=7 req.path=/"}
+	if fmt.Sprint(tb.logs) != fmt.Sprint(expected) {
+		t.Errorf("expected %q, got %q", expected, tb.logs)
+	}
+
+	// Records logged after the test has completed are dropped
+	tb.finish()
+	logger.Info("late")
+	if len(tb.logs) != len(expected) {
+		t.Errorf("expected no logs after the test completed, got %q", tb.logs)
+	}
+}
+
+func TestNewTestLoggerLevel(t *testing.T) {
+	t.Setenv(planks_slog.EnvPlanksEnvPrefix, "APP")
+	t.Setenv("APP_"+planks_slog.EnvLoggerLevel, "warn")
+
+	tb := &fakeTB{}
+	logger := NewTestLogger(tb)
+	logger.Info("info")
+	logger.Warn("warn")
+	if expected := []string{"level=WARN msg=warn"}; fmt.Sprint(tb.logs) != fmt.Sprint(expected) {
+		t.Errorf("expected %q, got %q", expected, tb.logs)
+	}
+
+	t.Setenv("APP_"+planks_slog.EnvLoggerLevel, "loud")
+	tb = &fakeTB{}
+	NewTestLogger(tb)
+	if len(tb.fatals) != 1 {
+		t.Errorf("expected an invalid level to fail the test, got %q", tb.fatals)
+	}
+}
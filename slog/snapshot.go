@@ -0,0 +1,120 @@
+package slog
+
+import (
+	"flag"
+	"io"
+	"log"
+	"log/slog"
+	"maps"
+	"slices"
+)
+
+// Snapshot captures the package state that loggers are built from and
+// installed into, and returns a function that restores it, so that a test can
+// reconfigure logging freely and defer the restore:
+//
+//	defer planks_slog.Snapshot()()
+//
+// The state covers the default logger (including the output and flags of the
+// log package, which slog.SetDefault redirects), the level of the default
+// logger installed by Init, the logger that Restore reinstalls, the
+// configuration returned by CurrentConfig, the registered attribute
+// transforms, event matchers, derived attributes and named loggers, the
+// logger name key, the drop callback and the flag set of RegisterFlags.
+// It does not cover background tasks and open files, such as the heartbeat,
+// the level provider and log files; release them with Close.
+//
+// Snapshot and the restore function are not atomic with respect to other
+// goroutines: do not log or reconfigure concurrently with them, and do not
+// use them in parallel tests.
+func Snapshot() func() {
+	s := captureState()
+	return s.restore
+}
+
+// packageState is the package state captured by Snapshot.
+type packageState struct {
+	defaultLogger      *slog.Logger
+	logWriter          io.Writer
+	logFlags           int
+	level              slog.Level
+	installedRoot      slog.Handler
+	installedSwappable *swappableHandler
+	preInit            *previousDefault
+	config             *Config
+	attrTransforms     map[string][]func(slog.Value) slog.Value
+	eventMatchers      []eventMatcher
+	derivedAttrs       []func(r slog.Record) (slog.Attr, bool)
+	registry           map[string]*slog.Logger
+	loggerNameKey      string
+	dropCallback       *func(r slog.Record, reason string)
+	flagSet            *flag.FlagSet
+}
+
+// captureState returns the current package state.
+func captureState() *packageState {
+	s := &packageState{
+		defaultLogger: slog.Default(),
+		logWriter:     log.Writer(),
+		logFlags:      log.Flags(),
+		level:         defaultLevel.Level(),
+		config:        currentConfig.Load(),
+		dropCallback:  dropCallback.Load(),
+	}
+
+	installedMu.Lock()
+	s.installedRoot, s.installedSwappable, s.preInit = installedRoot, installedSwappable, preInit
+	installedMu.Unlock()
+
+	attrTransformsMu.RLock()
+	s.attrTransforms = cloneAttrTransforms(attrTransforms)
+	attrTransformsMu.RUnlock()
+
+	s.eventMatchers = registeredEventMatchers()
+	s.derivedAttrs = registeredDerivedAttrs()
+
+	registryMu.RLock()
+	s.registry = maps.Clone(registry)
+	s.loggerNameKey = loggerNameKey
+	registryMu.RUnlock()
+
+	flagSetMu.Lock()
+	s.flagSet = flagSet
+	flagSetMu.Unlock()
+	return s
+}
+
+// restore restores the package state captured in s.
+func (s *packageState) restore() {
+	slog.SetDefault(s.defaultLogger)
+	log.SetOutput(s.logWriter)
+	log.SetFlags(s.logFlags)
+	defaultLevel.Set(s.level)
+	currentConfig.Store(s.config)
+	dropCallback.Store(s.dropCallback)
+
+	installedMu.Lock()
+	installedRoot, installedSwappable, preInit = s.installedRoot, s.installedSwappable, s.preInit
+	installedMu.Unlock()
+
+	attrTransformsMu.Lock()
+	attrTransforms = cloneAttrTransforms(s.attrTransforms)
+	attrTransformsMu.Unlock()
+
+	eventMatchersMu.Lock()
+	eventMatchers = slices.Clone(s.eventMatchers)
+	eventMatchersMu.Unlock()
+
+	derivedAttrsMu.Lock()
+	derivedAttrs = slices.Clone(s.derivedAttrs)
+	derivedAttrsMu.Unlock()
+
+	registryMu.Lock()
+	registry = maps.Clone(s.registry)
+	loggerNameKey = s.loggerNameKey
+	registryMu.Unlock()
+
+	flagSetMu.Lock()
+	flagSet = s.flagSet
+	flagSetMu.Unlock()
+}
@@ -0,0 +1,62 @@
+package slog
+
+import (
+	"flag"
+	"log"
+	"log/slog"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	originalDefault := slog.Default()
+	logWriter, logFlags := log.Writer(), log.Flags()
+	originalLevel := defaultLevel.Level()
+	originalConfig := currentConfig.Load()
+	originalFlagSet := flagSet
+	originalMatchers := len(registeredEventMatchers())
+
+	restore := Snapshot()
+
+	clearEnvVars()
+	t.Setenv(EnvLoggerLevel, "debug")
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, filepath.Join(t.TempDir(), "test.log"))
+	Init()
+	defaultLevel.Set(slog.LevelError)
+	RegisterAttrTransform("k", func(slog.Value) slog.Value { return slog.StringValue("changed") })
+	RegisterEventMatcher(func(slog.Record) bool { return true }, func(slog.Record) {})
+	RegisterLogger("snapshot", slog.New(slog.DiscardHandler))
+	SetLoggerNameKey("component")
+	SetDropCallback(func(slog.Record, string) {})
+	RegisterFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+
+	restore()
+
+	if slog.Default() != originalDefault {
+		t.Errorf("expected the default logger to be restored")
+	}
+	if log.Writer() != logWriter || log.Flags() != logFlags {
+		t.Errorf("expected the log package output and flags to be restored")
+	}
+	if defaultLevel.Level() != originalLevel {
+		t.Errorf("expected the level %v, got %v", originalLevel, defaultLevel.Level())
+	}
+	if currentConfig.Load() != originalConfig {
+		t.Errorf("expected the current configuration to be restored, got %+v", CurrentConfig())
+	}
+	if len(attrTransforms["k"]) != 0 || len(registeredEventMatchers()) != originalMatchers {
+		t.Errorf("expected the attribute transforms and event matchers to be restored")
+	}
+	if lookupLogger("snapshot") != nil {
+		t.Errorf("expected the named logger to be removed")
+	}
+	if loggerNameKey != DefaultLoggerNameKey || dropCallback.Load() != nil || flagSet != originalFlagSet {
+		t.Errorf("expected the logger name key, drop callback and flag set to be restored")
+	}
+
+}
@@ -0,0 +1,88 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// sortHandler is a wrapper handler that sorts the attributes of each record
+// by key, so that the output does not depend on the order of the logging
+// calls. The built-in attributes are emitted by the handlers and keep their
+// standard position. Attributes of groups are sorted within each group, and
+// attributes with equal keys keep their relative order.
+//
+// Attributes added with WithAttrs are held by the handler rather than passed
+// on, so that they can be sorted together with the record's attributes. They
+// are passed on, sorted, when a group is opened.
+type sortHandler struct {
+	next  slog.Handler
+	attrs []slog.Attr
+}
+
+// newSortHandler creates a new handler that sorts attributes by key.
+func newSortHandler(next slog.Handler) slog.Handler {
+	return &sortHandler{next: next}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *sortHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *sortHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, len(h.attrs)+r.NumAttrs())
+	attrs = append(attrs, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	sorted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	sorted.AddAttrs(sortAttrs(attrs)...)
+	return h.next.Handle(ctx, sorted)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *sortHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	held := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	held = append(held, h.attrs...)
+	return &sortHandler{next: h.next, attrs: append(held, attrs...)}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *sortHandler) WithGroup(name string) slog.Handler {
+	next := h.next
+	if len(h.attrs) > 0 {
+		next = next.WithAttrs(sortAttrs(append([]slog.Attr(nil), h.attrs...)))
+	}
+	return &sortHandler{next: next.WithGroup(name)}
+}
+
+// describe implements describer.
+func (h *sortHandler) describe() string {
+	return "sort"
+}
+
+// unwrap implements describer.
+func (h *sortHandler) unwrap() slog.Handler {
+	return h.next
+}
+
+// sortAttrs sorts attrs in place by key, recursing into groups, and returns
+// it. Values are resolved so that LogValuers returning groups are sorted too.
+func sortAttrs(attrs []slog.Attr) []slog.Attr {
+	for i, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			a.Value = slog.GroupValue(sortAttrs(slices.Clone(a.Value.Group()))...)
+		}
+		attrs[i] = a
+	}
+	slices.SortStableFunc(attrs, func(a, b slog.Attr) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return attrs
+}
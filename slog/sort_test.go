@@ -0,0 +1,59 @@
+package slog
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// groupValuer is a LogValuer that resolves to a group.
+type groupValuer struct{}
+
+func (groupValuer) LogValue() slog.Value {
+	return slog.GroupValue(slog.Int("z", 1), slog.Int("a", 2))
+}
+
+func TestSortHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare", SortAttrs: true}, &buf))
+
+	logger.With("zone", "eu", "app", "api").Info("test", "user", "u-1", slog.Group("req", "path", "/", "id", 7), "b", 1, "b", 0, "lv", groupValuer{})
+	expected := "msg=test app=api b=1 b=0 lv.a=2 lv.z=1 req.id=7 req.path=/ user=u-1 zone=eu"
+	if got := strings.TrimSuffix(buf.String(), "\n"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	// Attributes are sorted within each group
+	buf.Reset()
+	logger.With("zone", "eu", "app", "api").WithGroup("g").With("y", 1).Info("test", "x", 2)
+	expected = "msg=test app=api zone=eu g.x=2 g.y=1"
+	if got := strings.TrimSuffix(buf.String(), "\n"); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSortHandlerDeterministic(t *testing.T) {
+	var first, second bytes.Buffer
+	config := &Config{HandlerType: "json", SortAttrs: true}
+	slog.New(createHandler(config, &first)).Info("test", "b", 2, "a", 1, "c", 3)
+	slog.New(createHandler(config, &second)).Info("test", "c", 3, "a", 1, "b", 2)
+
+	cut := func(s string) string { return s[strings.Index(s, `"level"`):] }
+	if cut(first.String()) != cut(second.String()) {
+		t.Errorf("expected the same output regardless of call order, got %q and %q", first.String(), second.String())
+	}
+	if !strings.Contains(first.String(), `"msg":"test","a":1,"b":2,"c":3}`) {
+		t.Errorf("expected built-ins first and sorted attributes, got %q", first.String())
+	}
+}
+
+func TestReadConfigSortAttrs(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerSortAttrs: "true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.SortAttrs {
+		t.Errorf("expected SortAttrs to be enabled")
+	}
+}
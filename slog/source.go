@@ -0,0 +1,85 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+)
+
+// DefaultFlatSourceKey is the attribute key used for flattened source
+// information when no source key is configured.
+const DefaultFlatSourceKey = "caller"
+
+// replaceSource returns a ReplaceAttr function that renames and optionally
+// flattens the built-in source attribute, or nil if the config leaves the
+// source attribute unchanged.
+func replaceSource(config *Config) func([]string, slog.Attr) slog.Attr {
+	if config.SourceKey == "" && !config.SourceFlatten {
+		return nil
+	}
+
+	key := config.SourceKey
+	if key == "" {
+		key = DefaultFlatSourceKey
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) != 0 || a.Key != slog.SourceKey {
+			return a
+		}
+		if config.SourceFlatten {
+			if src, ok := a.Value.Any().(*slog.Source); ok {
+				return slog.String(key, src.File+":"+strconv.Itoa(src.Line))
+			}
+		}
+		a.Key = key
+		return a
+	}
+}
+
+// sourceLevelHandler is a wrapper handler that removes the program counter
+// from records below level, so that the handler neither resolves nor logs
+// their source position.
+type sourceLevelHandler struct {
+	next  slog.Handler
+	level slog.Leveler
+}
+
+// newSourceLevelHandler creates a new handler that keeps source information
+// only for records at or above level.
+func newSourceLevelHandler(next slog.Handler, level slog.Leveler) slog.Handler {
+	return &sourceLevelHandler{next: next, level: level}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *sourceLevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *sourceLevelHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < h.level.Level() {
+		r.PC = 0
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *sourceLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &sourceLevelHandler{next: h.next.WithAttrs(attrs), level: h.level}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *sourceLevelHandler) WithGroup(name string) slog.Handler {
+	return &sourceLevelHandler{next: h.next.WithGroup(name), level: h.level}
+}
+
+// describe implements describer.
+func (h *sourceLevelHandler) describe() string {
+	return "source_level"
+}
+
+// unwrap implements describer.
+func (h *sourceLevelHandler) unwrap() slog.Handler {
+	return h.next
+}
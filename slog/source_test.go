@@ -0,0 +1,146 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestReplaceSource(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+
+	tests := []struct {
+		name       string
+		config     *Config
+		key        string
+		flattened  bool
+		missingKey string
+	}{
+		{
+			name:   "Default Nested",
+			config: &Config{},
+			key:    slog.SourceKey,
+		},
+		{
+			name:       "Renamed Nested",
+			config:     &Config{SourceKey: "src"},
+			key:        "src",
+			missingKey: slog.SourceKey,
+		},
+		{
+			name:       "Flattened",
+			config:     &Config{SourceFlatten: true},
+			key:        DefaultFlatSourceKey,
+			flattened:  true,
+			missingKey: slog.SourceKey,
+		},
+		{
+			name:       "Flattened With Custom Key",
+			config:     &Config{SourceFlatten: true, SourceKey: "at"},
+			key:        "at",
+			flattened:  true,
+			missingKey: slog.SourceKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.config.HandlerType = "json"
+			tt.config.AddSource = true
+			logger := slog.New(createHandler(tt.config, &buf))
+
+			_, _, line, _ := runtime.Caller(0)
+			logger.Info("test message") // must stay on the line after runtime.Caller
+			line++
+
+			var record map[string]json.RawMessage
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+			}
+			if tt.missingKey != "" {
+				if _, ok := record[tt.missingKey]; ok {
+					t.Errorf("expected no %q attribute in %s", tt.missingKey, buf.String())
+				}
+			}
+
+			if tt.flattened {
+				var caller string
+				if err := json.Unmarshal(record[tt.key], &caller); err != nil {
+					t.Fatalf("expected string %q attribute in %s: %v", tt.key, buf.String(), err)
+				}
+				if want := file + ":" + strconv.Itoa(line); caller != want {
+					t.Errorf("expected %q, got %q", want, caller)
+				}
+				return
+			}
+
+			var src slog.Source
+			if err := json.Unmarshal(record[tt.key], &src); err != nil {
+				t.Fatalf("expected nested %q attribute in %s: %v", tt.key, buf.String(), err)
+			}
+			if src.File != file || src.Line != line {
+				t.Errorf("expected %s:%d, got %s:%d", file, line, src.File, src.Line)
+			}
+		})
+	}
+}
+
+func TestReadConfigSource(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{
+		EnvLoggerSourceKey:     "caller",
+		EnvLoggerSourceFlatten: "true",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.SourceKey != "caller" || !config.SourceFlatten {
+		t.Errorf("expected SourceKey 'caller' and SourceFlatten, got %q and %v", config.SourceKey, config.SourceFlatten)
+	}
+}
+
+func TestSourceLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "json", SourceLevel: slog.LevelWarn}, &buf))
+
+	for _, level := range []slog.Level{slog.LevelInfo, slog.LevelWarn, slog.LevelError} {
+		buf.Reset()
+		logger.Log(context.Background(), level, "test")
+
+		var record map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("failed to parse output %q: %v", buf.String(), err)
+		}
+		_, hasSource := record[slog.SourceKey]
+		if expected := level >= slog.LevelWarn; hasSource != expected {
+			t.Errorf("%v: expected source %v, got %v", level, expected, record)
+		}
+	}
+}
+
+func TestReadConfigSourceLevel(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerSourceLevel: "warn"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.SourceLevel != slog.LevelWarn {
+		t.Errorf("expected source level WARN, got %v", config.SourceLevel)
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerSourceLevel: "loud"}); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("expected ErrInvalidLevel, got %v", err)
+	}
+}
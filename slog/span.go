@@ -0,0 +1,25 @@
+package slog
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// StartSpan starts a lightweight span for timing an operation through logs.
+// It returns a copy of ctx whose context logger opens a group with the given
+// name, as Scope does, and a function that ends the span. Ending the span logs
+// "span ended" at info level through the span's logger, with the start time
+// and duration of the span as "start" and "duration" attributes in its group.
+// The end function logs only on its first call, so it can be deferred and
+// also called early.
+func StartSpan(ctx context.Context, name string) (context.Context, func()) {
+	start := time.Now()
+	ctx = Scope(ctx, name)
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			FromContext(ctx).InfoContext(ctx, "span ended", "start", start, "duration", time.Since(start))
+		})
+	}
+}
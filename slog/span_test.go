@@ -0,0 +1,73 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartSpan(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil)).With("RequestID", "req-1")
+	ctx := WithContext(context.Background(), logger)
+
+	spanCtx, end := StartSpan(ctx, "db")
+	FromContext(spanCtx).InfoContext(spanCtx, "query", "rows", 3)
+	time.Sleep(time.Millisecond)
+	end()
+	end()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a log within the span and one on its end, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "RequestID=req-1") || !strings.Contains(lines[0], "db.rows=3") {
+		t.Errorf("expected the span group on logs within it, got %q", lines[0])
+	}
+	m := regexp.MustCompile(`msg="span ended" RequestID=req-1 db.start=\S+ db.duration=(\S+)$`).FindStringSubmatch(lines[1])
+	if m == nil {
+		t.Fatalf("unexpected span end log: %q", lines[1])
+	}
+	if d, err := time.ParseDuration(m[1]); err != nil || d < time.Millisecond {
+		t.Errorf("expected a duration of at least 1ms, got %q", m[1])
+	}
+
+	// The original context is unaffected by the span
+	buf.Reset()
+	FromContext(ctx).InfoContext(ctx, "outside", "rows", 3)
+	if strings.Contains(buf.String(), "db.") {
+		t.Errorf("expected no span group outside of it, got %q", buf.String())
+	}
+}
+
+func TestStartSpanContextLogger(t *testing.T) {
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	for _, strict := range []bool{false, true} {
+		t.Run(fmt.Sprintf("strict=%v", strict), func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.SetDefault(slog.New(createHandler(&Config{HandlerType: "text", ContextStrict: strict}, &buf)))
+
+			// The span end record passes through the tags and error
+			// loggers once, and is not dropped in strict mode
+			ctx := WithError(Tags(context.Background(), "billing"), errors.New("boom"))
+			_, end := StartSpan(ctx, "db")
+			end()
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != 1 || !strings.Contains(lines[0], `msg="span ended"`) {
+				t.Fatalf("expected one span end log, got %q", buf.String())
+			}
+			if strings.Count(lines[0], "tags=[billing]") != 1 || strings.Count(lines[0], "error=boom") != 1 {
+				t.Errorf("expected the tags and the error once, got %q", lines[0])
+			}
+		})
+	}
+}
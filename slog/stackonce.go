@@ -0,0 +1,122 @@
+package slog
+
+import (
+	"container/list"
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"sync"
+)
+
+// stackOnceCacheSize is the number of distinct stack traces remembered by
+// LOGGER_STACKTRACE_ONCE. A stack evicted from the cache is logged in full
+// again the next time it occurs.
+const stackOnceCacheSize = 256
+
+// stackOnceHandler is a wrapper handler that logs each distinct stack trace
+// in full only once. A string attribute with the key "stack", as logged for
+// recovered panics by NewHTTPMiddleware and Job.Run, is tagged with a "stack_id"
+// attribute holding the hash of the stack the first time it is seen; later
+// records carrying the same stack log "same as <stack_id>" in its place.
+type stackOnceHandler struct {
+	next slog.Handler
+	seen *stackCache
+}
+
+// newStackOnceHandler creates a new handler that logs repeated stack traces by reference.
+func newStackOnceHandler(next slog.Handler) slog.Handler {
+	return &stackOnceHandler{next: next, seen: newStackCache(stackOnceCacheSize)}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *stackOnceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *stackOnceHandler) Handle(ctx context.Context, r slog.Record) error {
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		found = a.Key == "stack" && a.Value.Resolve().Kind() == slog.KindString
+		return !found
+	})
+	if !found {
+		return h.next.Handle(ctx, r)
+	}
+
+	replaced := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key != "stack" || a.Value.Resolve().Kind() != slog.KindString {
+			replaced.AddAttrs(a)
+			return true
+		}
+		id := stackID(a.Value.Resolve().String())
+		if h.seen.add(id) {
+			replaced.AddAttrs(a, slog.String("stack_id", id))
+		} else {
+			replaced.AddAttrs(slog.String("stack", "same as "+id))
+		}
+		return true
+	})
+	return h.next.Handle(ctx, replaced)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *stackOnceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &stackOnceHandler{next: h.next.WithAttrs(attrs), seen: h.seen}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *stackOnceHandler) WithGroup(name string) slog.Handler {
+	return &stackOnceHandler{next: h.next.WithGroup(name), seen: h.seen}
+}
+
+// describe implements describer.
+func (h *stackOnceHandler) describe() string {
+	return "stacktrace_once"
+}
+
+// unwrap implements describer.
+func (h *stackOnceHandler) unwrap() slog.Handler {
+	return h.next
+}
+
+// stackID returns the reference of a stack trace, the hex FNV-1a hash of its text.
+func stackID(stack string) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(stack))
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// stackCache is a bounded set of stack ids that evicts the least recently
+// seen id when it is full. It is shared by the handlers derived with
+// WithAttrs and WithGroup.
+type stackCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List // of string, most recently seen first
+	ids   map[string]*list.Element
+}
+
+// newStackCache creates a cache that remembers up to size ids.
+func newStackCache(size int) *stackCache {
+	return &stackCache{size: size, order: list.New(), ids: make(map[string]*list.Element, size)}
+}
+
+// add marks id as seen and reports whether it was not seen before.
+func (c *stackCache) add(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.ids[id]; ok {
+		c.order.MoveToFront(e)
+		return false
+	}
+	c.ids[id] = c.order.PushFront(id)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.ids, oldest.Value.(string))
+	}
+	return true
+}
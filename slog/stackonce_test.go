@@ -0,0 +1,70 @@
+package slog
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStackOnceHandler(t *testing.T) {
+	buffer := newTestBufferHandler()
+	logger := slog.New(newStackOnceHandler(buffer))
+
+	err := errors.New("boom")
+	for range 2 {
+		logger.Error("request panicked", "panic", err, "stack", "goroutine 1 [running]:\nmain.main()")
+	}
+	logger.Error("request panicked", "panic", err, "stack", "goroutine 7 [running]:\nmain.worker()")
+	logger.Info("no stack", "stack", 42)
+
+	if len(buffer.logs) != 4 {
+		t.Fatalf("expected 4 logs, got %d", len(buffer.logs))
+	}
+	_, id, ok := strings.Cut(buffer.logs[0], "stack_id=")
+	id = strings.TrimSuffix(id, "]")
+	if !ok || !strings.Contains(buffer.logs[0], "main.main()") {
+		t.Fatalf("expected the full stack and its id in %q", buffer.logs[0])
+	}
+	if want := "stack=same as " + id; !strings.Contains(buffer.logs[1], want) || strings.Contains(buffer.logs[1], "main.main()") {
+		t.Errorf("expected %q in place of the repeated stack, got %q", want, buffer.logs[1])
+	}
+	if !strings.Contains(buffer.logs[2], "main.worker()") || strings.Contains(buffer.logs[2], id) {
+		t.Errorf("expected a different stack in full, got %q", buffer.logs[2])
+	}
+	if strings.Contains(buffer.logs[3], "stack_id") {
+		t.Errorf("expected non-string stack attributes to be left alone, got %q", buffer.logs[3])
+	}
+}
+
+func TestReadConfigStackOnce(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerStackOnce: "1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.StacktraceOnce {
+		t.Error("expected StacktraceOnce to be enabled")
+	}
+	if got := describeHandler(wrapHandler(config, newTestBufferHandler())); !slices.Contains(got, "stacktrace_once") {
+		t.Errorf("expected a stacktrace_once wrapper, got %v", got)
+	}
+}
+
+func TestStackCacheEviction(t *testing.T) {
+	cache := newStackCache(2)
+	for _, id := range []string{"a", "b", "a", "c"} {
+		cache.add(id)
+	}
+	// "b" was the least recently seen id when "c" was added.
+	if cache.add("a") {
+		t.Error("expected a to be remembered")
+	}
+	if !cache.add("b") {
+		t.Error("expected b to have been evicted")
+	}
+}
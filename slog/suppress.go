@@ -0,0 +1,90 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// suppressHandler is a wrapper handler that counts the records below level
+// that reach Handle, such as those demoted by message level overrides, and
+// drops them. Each time every more records of a level have been suppressed, it
+// logs an info note saying so, since silence is otherwise indistinguishable
+// from nothing happening.
+//
+// Enabled is left to the handler it wraps, so that callers skip building the
+// records of disabled levels; those records are not counted.
+type suppressHandler struct {
+	next    slog.Handler
+	level   slog.Leveler
+	every   int
+	counter *suppressCounter
+}
+
+// suppressCounter counts the suppressed records per level. It is shared by
+// the handlers derived from one suppressHandler.
+type suppressCounter struct {
+	mu     sync.Mutex
+	counts map[slog.Level]int
+}
+
+// newSuppressHandler creates a new handler that drops records below level and
+// logs a note each time every records of a level have been suppressed.
+func newSuppressHandler(next slog.Handler, level slog.Leveler, every int) slog.Handler {
+	return &suppressHandler{
+		next:    next,
+		level:   level,
+		every:   every,
+		counter: &suppressCounter{counts: make(map[slog.Level]int)},
+	}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *suppressHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *suppressHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= h.level.Level() {
+		return h.next.Handle(ctx, r)
+	}
+
+	h.counter.mu.Lock()
+	h.counter.counts[r.Level]++
+	note := h.counter.counts[r.Level] >= h.every
+	if note {
+		h.counter.counts[r.Level] = 0
+	}
+	h.counter.mu.Unlock()
+	if !note {
+		return nil
+	}
+
+	msg := fmt.Sprintf("suppressed %d %s records (lower LOGGER_LEVEL to see them)", h.every, r.Level)
+	n := slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0)
+	n.AddAttrs(slog.String("suppressed_level", r.Level.String()), slog.Int("suppressed", h.every))
+	return h.next.Handle(ctx, n)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *suppressHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &suppressHandler{next: h.next.WithAttrs(attrs), level: h.level, every: h.every, counter: h.counter}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *suppressHandler) WithGroup(name string) slog.Handler {
+	return &suppressHandler{next: h.next.WithGroup(name), level: h.level, every: h.every, counter: h.counter}
+}
+
+// describe implements describer.
+func (h *suppressHandler) describe() string {
+	return fmt.Sprintf("suppress(%d)", h.every)
+}
+
+// unwrap implements describer.
+func (h *suppressHandler) unwrap() slog.Handler {
+	return h.next
+}
@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSuppressHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{
+		HandlerType:    "bare",
+		Level:          slog.LevelWarn,
+		NoteSuppressed: 3,
+		MsgLevelOverrides: []MsgLevelOverride{
+			{Substring: "noisy", Level: slog.LevelDebug},
+			{Substring: "chatty", Level: slog.LevelInfo},
+		},
+	}, &buf))
+
+	// Disabled levels are skipped by the callers and not counted
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("expected the debug level to be disabled")
+	}
+	for range 5 {
+		logger.Debug("debug")
+	}
+	// Demoted records reach the handler and are counted at their new level
+	for range 7 {
+		logger.Warn("noisy")
+	}
+	for range 3 {
+		logger.With("k", "v").Warn("chatty")
+	}
+	logger.Warn("warn")
+
+	expected := []string{
+		`msg="suppressed 3 DEBUG records (lower LOGGER_LEVEL to see them)" suppressed_level=DEBUG suppressed=3`,
+		`msg="suppressed 3 DEBUG records (lower LOGGER_LEVEL to see them)" suppressed_level=DEBUG suppressed=3`,
+		`msg="suppressed 3 INFO records (lower LOGGER_LEVEL to see them)" k=v suppressed_level=INFO suppressed=3`,
+		`msg=warn`,
+	}
+	if got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(expected, "\n"), buf.String())
+	}
+}
+
+func TestSuppressHandlerDynamicLevel(t *testing.T) {
+	var level slog.LevelVar
+	level.Set(slog.LevelInfo)
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare", leveler: &level, NoteSuppressed: 100}, &buf))
+
+	logger.Debug("hidden")
+	level.Set(slog.LevelDebug)
+	logger.Debug("shown")
+	if expected := "msg=shown\n"; buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestReadConfigNoteSuppressed(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerNoteSuppressed: "1000"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.NoteSuppressed != 1000 {
+		t.Errorf("expected 1000, got %d", config.NoteSuppressed)
+	}
+
+	for _, every := range []string{"0", "-5", "often"} {
+		if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerNoteSuppressed: every}); !errors.Is(err, ErrInvalidNoteSuppressed) {
+			t.Errorf("%q: expected ErrInvalidNoteSuppressed, got %v", every, err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// swapTarget holds the handler that a swappableHandler routes records to.
+type swapTarget struct {
+	handler slog.Handler
+}
+
+// derivedTarget caches the handler obtained by replaying a swappableHandler's
+// attributes and groups onto a swap target.
+type derivedTarget struct {
+	target  *swapTarget
+	handler slog.Handler
+}
+
+// handlerOp is a WithAttrs or WithGroup call recorded by a handler so it can
+// be replayed onto another handler.
+type handlerOp struct {
+	attrs []slog.Attr
+	group string
+}
+
+// swappableHandler routes records to a handler held in an atomic pointer, so
+// that the handler can be replaced without reinstalling the loggers using it.
+//
+// Handlers derived with WithAttrs and WithGroup share the pointer and follow
+// swaps: they replay their attributes and groups onto the current handler,
+// caching the result until the next swap.
+type swappableHandler struct {
+	root  *atomic.Pointer[swapTarget]
+	ops   []handlerOp
+	cache atomic.Pointer[derivedTarget]
+}
+
+// newSwappableHandler creates a new swappable handler that initially routes to handler.
+func newSwappableHandler(handler slog.Handler) *swappableHandler {
+	h := &swappableHandler{root: new(atomic.Pointer[swapTarget])}
+	h.swap(handler)
+	return h
+}
+
+// swap replaces the handler that h and all handlers derived from it route to.
+func (h *swappableHandler) swap(handler slog.Handler) {
+	h.root.Store(&swapTarget{handler: handler})
+}
+
+// current returns the handler that records are currently routed to.
+func (h *swappableHandler) current() slog.Handler {
+	target := h.root.Load()
+	if len(h.ops) == 0 {
+		return target.handler
+	}
+	if derived := h.cache.Load(); derived != nil && derived.target == target {
+		return derived.handler
+	}
+
+	handler := applyHandlerOps(target.handler, h.ops)
+	h.cache.Store(&derivedTarget{target: target, handler: handler})
+	return handler
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *swappableHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.current().Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *swappableHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.current().Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *swappableHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.derive(handlerOp{attrs: attrs})
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *swappableHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.derive(handlerOp{group: name})
+}
+
+// describe implements describer.
+func (h *swappableHandler) describe() string {
+	return "swappable"
+}
+
+// unwrap implements describer.
+func (h *swappableHandler) unwrap() slog.Handler {
+	return h.current()
+}
+
+// derive returns a handler sharing h's swap target with op appended to its operations.
+func (h *swappableHandler) derive(op handlerOp) *swappableHandler {
+	ops := make([]handlerOp, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &swappableHandler{
+		root: h.root,
+		ops:  append(ops, op),
+	}
+}
+
+// applyHandlerOps replays the recorded operations onto handler.
+func applyHandlerOps(handler slog.Handler, ops []handlerOp) slog.Handler {
+	for _, op := range ops {
+		if op.attrs != nil {
+			handler = handler.WithAttrs(op.attrs)
+		} else {
+			handler = handler.WithGroup(op.group)
+		}
+	}
+	return handler
+}
+
+var (
+	// installedMu guards the installation of the default logger by Init.
+	installedMu sync.Mutex
+	// installedRoot is the handler of the default logger installed by Init.
+	installedRoot slog.Handler
+	// installedSwappable is the swappable handler behind installedRoot.
+	installedSwappable *swappableHandler
+)
+
+// installDefault installs a new context-aware default logger whose handler
+// can be replaced later with swapDefaultHandler. If strict is set, the
+// context-aware handler fails on context logger cycles; dedup is the policy
+// for duplicate keys in the records it passes to context loggers.
+func installDefault(handler slog.Handler, strict bool, dedup string) {
+	installedMu.Lock()
+	defer installedMu.Unlock()
+
+	capturePreInit()
+	installedSwappable = newSwappableHandler(handler)
+	installedRoot = &contextAwareHandler{internal: installedSwappable, strict: strict, dedup: dedup}
+	slog.SetDefault(slog.New(installedRoot))
+}
+
+// swapDefaultHandler replaces the handler behind the default logger installed
+// by Init. Loggers derived from the default logger follow the swap.
+// It reports false if the current default logger was not installed by Init
+// or was installed with a different strict mode or dedup policy, which
+// requires a new context-aware handler.
+func swapDefaultHandler(handler slog.Handler, strict bool, dedup string) bool {
+	installedMu.Lock()
+	defer installedMu.Unlock()
+
+	if installedSwappable == nil || slog.Default().Handler() != installedRoot {
+		return false
+	}
+	if root, ok := installedRoot.(*contextAwareHandler); !ok || root.strict != strict || root.dedup != dedup {
+		return false
+	}
+	installedSwappable.swap(handler)
+	return true
+}
@@ -0,0 +1,126 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// lockedBuffer is a bytes.Buffer safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSwappableHandler(t *testing.T) {
+	var first, second bytes.Buffer
+	swappable := newSwappableHandler(slog.NewTextHandler(&first, nil))
+	logger := slog.New(swappable)
+	derived := logger.With("RequestID", "req-1").WithGroup("g")
+
+	derived.Info("before", "k", 1)
+	swappable.swap(slog.NewTextHandler(&second, nil))
+	derived.Info("after", "k", 2)
+	logger.Info("root")
+
+	if out := first.String(); !strings.Contains(out, "msg=before RequestID=req-1 g.k=1") || strings.Contains(out, "after") {
+		t.Errorf("unexpected output before swap: %q", out)
+	}
+	out := second.String()
+	if !strings.Contains(out, "msg=after RequestID=req-1 g.k=2") || !strings.Contains(out, "msg=root") {
+		t.Errorf("expected derived and root loggers to follow the swap, got %q", out)
+	}
+}
+
+func TestSwappableHandlerConcurrency(t *testing.T) {
+	buffers := []*lockedBuffer{{}, {}}
+	swappable := newSwappableHandler(slog.NewTextHandler(buffers[0], nil))
+	derived := slog.New(swappable).With("worker", true)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				derived.Info("message")
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			swappable.swap(slog.NewTextHandler(buffers[i%2], nil))
+		}
+	}()
+	wg.Wait()
+
+	total := strings.Count(buffers[0].String(), "msg=message worker=true\n") +
+		strings.Count(buffers[1].String(), "msg=message worker=true\n")
+	if total != 800 {
+		t.Errorf("expected 800 complete records across both handlers, got %d", total)
+	}
+}
+
+func TestInitSwapsHandler(t *testing.T) {
+	// Save original environment variables and default logger
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+
+	dir := t.TempDir()
+	firstPath := filepath.Join(dir, "first.log")
+	secondPath := filepath.Join(dir, "second.log")
+
+	clearEnvVars()
+	t.Setenv(EnvLoggerWriter, "file")
+	t.Setenv(EnvLoggerWriterFilePath, firstPath)
+	Init()
+
+	installed := slog.Default()
+	derived := installed.With("RequestID", "req-1")
+	ctx := WithContext(context.Background(), derived)
+	slog.InfoContext(ctx, "before")
+
+	t.Setenv(EnvLoggerWriterFilePath, secondPath)
+	Init()
+
+	if slog.Default() != installed {
+		t.Errorf("expected Init to keep the installed default logger")
+	}
+	slog.InfoContext(ctx, "after")
+
+	first, _ := os.ReadFile(firstPath)
+	second, _ := os.ReadFile(secondPath)
+	if !strings.Contains(string(first), "msg=before RequestID=req-1") || strings.Contains(string(first), "after") {
+		t.Errorf("unexpected first log file content: %q", first)
+	}
+	if !strings.Contains(string(second), "msg=after RequestID=req-1") {
+		t.Errorf("expected derived context logger to follow the swap, got %q", second)
+	}
+
+	// A default logger replaced by the user is not swapped
+	slog.SetDefault(originalDefault)
+	if swapDefaultHandler(slog.DiscardHandler, false, "") {
+		t.Errorf("expected no swap when the default logger was replaced")
+	}
+}
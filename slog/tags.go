@@ -0,0 +1,76 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// Tags returns a copy of ctx whose context logger adds the given tags to each
+// record it logs, as a "tags" attribute holding a []string. Tags accumulate:
+// the tags of earlier Tags calls on ctx are kept, and a tag already present is
+// not added again, so each tag appears once, in order of its first addition.
+// Empty tags are ignored. The context logger is based on the logger already
+// stored in ctx, or slog.Default().
+//
+// Like other attributes logged through the context logger, the tags are
+// nested in the groups opened by Scope.
+func Tags(ctx context.Context, tags ...string) context.Context {
+	return WithContext(ctx, newContextLogger(newTagsHandler(contextLoggerBase(ctx), tags)))
+}
+
+// tagsHandler is a wrapper handler that adds its tags to each record.
+type tagsHandler struct {
+	next slog.Handler
+	tags []string
+}
+
+// newTagsHandler creates a new handler that adds tags to the records logged
+// to next. If next is itself a tags handler, its tags are merged with tags.
+func newTagsHandler(next slog.Handler, tags []string) slog.Handler {
+	var merged []string
+	if h, ok := next.(*tagsHandler); ok {
+		next = h.next
+		merged = append(merged, h.tags...)
+	}
+	for _, tag := range tags {
+		if tag != "" && !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return &tagsHandler{next: next, tags: merged}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *tagsHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *tagsHandler) Handle(ctx context.Context, r slog.Record) error {
+	if len(h.tags) > 0 {
+		r = r.Clone()
+		r.AddAttrs(slog.Any("tags", append([]string(nil), h.tags...)))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *tagsHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &tagsHandler{next: h.next.WithAttrs(attrs), tags: h.tags}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *tagsHandler) WithGroup(name string) slog.Handler {
+	return &tagsHandler{next: h.next.WithGroup(name), tags: h.tags}
+}
+
+// describe implements describer.
+func (h *tagsHandler) describe() string {
+	return "tags"
+}
+
+// unwrap implements describer.
+func (h *tagsHandler) unwrap() slog.Handler {
+	return h.next
+}
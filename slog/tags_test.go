@@ -0,0 +1,64 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "json", Level: slog.LevelInfo}, &buf))
+	ctx := WithContext(context.Background(), logger)
+
+	ctx = Tags(ctx, "billing", "retry")
+	ctx = Tags(ctx, "retry", "", "slow", "billing")
+	FromContext(ctx).InfoContext(ctx, "charged")
+	logger.InfoContext(context.Background(), "untagged")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if want := `"tags":["billing","retry","slow"]`; !strings.Contains(lines[0], want) {
+		t.Errorf("expected %s in %s", want, lines[0])
+	}
+	if strings.Contains(lines[1], "tags") {
+		t.Errorf("expected no tags outside the context, got %s", lines[1])
+	}
+	if names := describeHandler(FromContext(ctx).Handler()); len(names) < 2 || names[1] != "tags" || strings.Count(strings.Join(names, ","), "tags") != 1 {
+		t.Errorf("expected a single tags handler, got %v", names)
+	}
+}
+
+func TestTagsLoggedOnce(t *testing.T) {
+	originalDefault := slog.Default()
+	defer slog.SetDefault(originalDefault)
+	var buf bytes.Buffer
+	slog.SetDefault(slog.New(createHandler(&Config{HandlerType: "text", Level: slog.LevelInfo}, &buf)))
+
+	ctx := WithLazyAttrs(Tags(context.Background(), "billing"), func(ctx context.Context) []slog.Attr {
+		return []slog.Attr{slog.String("lazy", "1")}
+	})
+
+	// Through the context logger directly, as the Tags documentation shows,
+	// through a logger derived from it and through the default logger
+	FromContext(ctx).InfoContext(ctx, "direct")
+	FromContext(ctx).With("k", "v").InfoContext(ctx, "derived")
+	slog.InfoContext(ctx, "default")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", buf.String())
+	}
+	for _, line := range lines {
+		if strings.Count(line, "tags=[billing]") != 1 || strings.Count(line, "lazy=1") != 1 {
+			t.Errorf("expected the tags and lazy attributes once, got %s", line)
+		}
+	}
+	if !strings.Contains(lines[1], "k=v") {
+		t.Errorf("expected the derived logger's attributes, got %s", lines[1])
+	}
+}
@@ -0,0 +1,85 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// timeoutHandler is a wrapper handler that abandons records whose handling,
+// typically a write to a blocked network or file writer, takes longer than a
+// timeout, so that a slow destination cannot hang the logging goroutine.
+//
+// Each record is handled on a separate goroutine. When the timeout expires,
+// the record is reported to the drop callback with DropReasonTimeout and Handle
+// returns; the abandoned write is left to finish in the background, so the
+// record may still be written late, or not at all if the write never returns.
+// While an abandoned write is outstanding, further records are dropped right
+// away instead of piling up behind it.
+type timeoutHandler struct {
+	next    slog.Handler
+	timeout time.Duration
+	stalled *atomic.Int32 // number of abandoned writes, shared by derived handlers
+}
+
+// newTimeoutHandler creates a new handler that abandons records not handled
+// within timeout.
+func newTimeoutHandler(next slog.Handler, timeout time.Duration) slog.Handler {
+	return &timeoutHandler{next: next, timeout: timeout, stalled: new(atomic.Int32)}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *timeoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *timeoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	if h.stalled.Load() > 0 {
+		reportDrop(r, DropReasonTimeout)
+		return nil
+	}
+
+	// The record is handled on another goroutine that may outlive this call.
+	r = r.Clone()
+	done := make(chan error, 1)
+	go func() {
+		done <- h.next.Handle(ctx, r)
+	}()
+
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		h.stalled.Add(1)
+		go func() {
+			<-done
+			h.stalled.Add(-1)
+		}()
+		reportDrop(r, DropReasonTimeout)
+		return nil
+	}
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *timeoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &timeoutHandler{next: h.next.WithAttrs(attrs), timeout: h.timeout, stalled: h.stalled}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *timeoutHandler) WithGroup(name string) slog.Handler {
+	return &timeoutHandler{next: h.next.WithGroup(name), timeout: h.timeout, stalled: h.stalled}
+}
+
+// describe implements describer.
+func (h *timeoutHandler) describe() string {
+	return "timeout"
+}
+
+// unwrap implements describer.
+func (h *timeoutHandler) unwrap() slog.Handler {
+	return h.next
+}
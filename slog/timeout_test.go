@@ -0,0 +1,131 @@
+package slog
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingWriter is a lockedBuffer whose writes block while it is blocked.
+type blockingWriter struct {
+	lockedBuffer
+	unblock chan struct{}
+	once    sync.Once
+}
+
+func newBlockingWriter() *blockingWriter {
+	return &blockingWriter{unblock: make(chan struct{})}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.unblock
+	return w.lockedBuffer.Write(p)
+}
+
+func (w *blockingWriter) release() {
+	w.once.Do(func() { close(w.unblock) })
+}
+
+func TestTimeoutHandler(t *testing.T) {
+	defer SetDropCallback(nil)
+
+	var mu sync.Mutex
+	var dropped []string
+	SetDropCallback(func(r slog.Record, reason string) {
+		mu.Lock()
+		defer mu.Unlock()
+		dropped = append(dropped, r.Message+":"+reason)
+	})
+
+	w := newBlockingWriter()
+	defer w.release()
+	h := newTimeoutHandler(slog.NewTextHandler(w, nil), 10*time.Millisecond)
+	logger := slog.New(h)
+
+	start := time.Now()
+	logger.Info("slow")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the slow write to be abandoned, blocked for %v", elapsed)
+	}
+	// While the abandoned write is outstanding, records are dropped right away
+	logger.With("k", "v").Info("stalled")
+
+	mu.Lock()
+	if expected := "slow:timeout,stalled:timeout"; strings.Join(dropped, ",") != expected {
+		t.Errorf("expected drops %q, got %q", expected, dropped)
+	}
+	mu.Unlock()
+
+	// Once the writer recovers, records are written again
+	w.release()
+	stalled := h.(*timeoutHandler).stalled
+	for deadline := time.Now().Add(time.Second); stalled.Load() > 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the abandoned write to finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	logger.Info("recovered")
+
+	out := w.String()
+	if !strings.Contains(out, "msg=recovered") || strings.Contains(out, "msg=stalled") {
+		t.Errorf("unexpected output %q", out)
+	}
+}
+
+func TestReadConfigWriteTimeout(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerWriteTimeout: "250ms"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.WriteTimeout != 250*time.Millisecond {
+		t.Errorf("expected 250ms, got %v", config.WriteTimeout)
+	}
+
+	for _, timeout := range []string{"fast", "0", "-1s"} {
+		_, err := readConfigFromEnv(t, map[string]string{EnvLoggerWriteTimeout: timeout})
+		if !errors.Is(err, ErrInvalidWriteTimeout) {
+			t.Errorf("%q: expected ErrInvalidWriteTimeout, got %v", timeout, err)
+		}
+	}
+}
+
+func TestTimeoutInsideSampler(t *testing.T) {
+	defer SetDropCallback(nil)
+
+	// The records dropped by the sampler are reported on the logging
+	// goroutine, so no goroutine was started for them.
+	caller, _ := goroutineID()
+	var mu sync.Mutex
+	sampled, elsewhere := 0, 0
+	SetDropCallback(func(r slog.Record, reason string) {
+		id, _ := goroutineID()
+		mu.Lock()
+		defer mu.Unlock()
+		if reason == DropReasonSampled {
+			sampled++
+		}
+		if id != caller {
+			elsewhere++
+		}
+	})
+
+	config := &Config{SampleAdaptive: true, SampleTargetRPS: 0.001, WriteTimeout: time.Second}
+	logger := slog.New(wrapHandler(config, slog.NewTextHandler(io.Discard, nil)))
+	for range 20 {
+		logger.Info("request")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if sampled == 0 {
+		t.Fatal("expected records to be sampled out")
+	}
+	if elsewhere != 0 {
+		t.Errorf("expected every sampled-out record to be reported on the logging goroutine, %d were not", elsewhere)
+	}
+}
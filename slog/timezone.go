@@ -0,0 +1,38 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// loadTimezone loads the location named by LOGGER_TIMEZONE. If the location
+// cannot be loaded, for example because the system lacks the time zone
+// database, it reports a soft ErrInvalidTimezone error and falls back to UTC.
+func loadTimezone(config *Config, name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidTimezone, err)
+		if err := config.softError(err, "failed to load timezone, using UTC", "timezone", name); err != nil {
+			return err
+		}
+		loc = time.UTC
+	}
+	config.Timezone = loc
+	return nil
+}
+
+// replaceTimezone returns a ReplaceAttr function that converts the record time
+// to the configured timezone, or nil if no timezone is configured.
+func replaceTimezone(config *Config) func([]string, slog.Attr) slog.Attr {
+	if config.Timezone == nil {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && a.Key == slog.TimeKey && a.Value.Kind() == slog.KindTime {
+			a.Value = slog.TimeValue(a.Value.Time().In(config.Timezone))
+		}
+		return a
+	}
+}
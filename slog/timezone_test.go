@@ -0,0 +1,105 @@
+package slog
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadConfigTimezone(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerTimezone: "UTC"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Timezone != time.UTC || len(config.warnings) != 0 {
+		t.Errorf("expected UTC without warnings, got %v %v", config.Timezone, config.warnings)
+	}
+}
+
+func TestTimezoneMissingZone(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	// A zone missing from the database fails to load like a missing database
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerTimezone: "Nowhere/Missing"})
+	if err != nil {
+		t.Fatalf("expected a fallback instead of an error, got %v", err)
+	}
+	if config.Timezone != time.UTC {
+		t.Errorf("expected fallback to UTC, got %v", config.Timezone)
+	}
+	if len(config.warnings) != 1 {
+		t.Errorf("expected one warning, got %v", config.warnings)
+	}
+
+	_, err = readConfigFromEnv(t, map[string]string{
+		EnvLoggerTimezone: "Nowhere/Missing",
+		EnvPlanksStrict:   "true",
+	})
+	if !errors.Is(err, ErrInvalidTimezone) {
+		t.Errorf("expected ErrInvalidTimezone in strict mode, got %v", err)
+	}
+}
+
+func TestTimezoneFallbackWarning(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+	defer Close()
+
+	path := filepath.Join(t.TempDir(), "test.log")
+	clearEnvVars()
+	os.Setenv(EnvLoggerTimezone, "Nowhere/Missing")
+	os.Setenv(EnvLoggerWriter, "file")
+	os.Setenv(EnvLoggerWriterFilePath, path)
+
+	logger, err := Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logger.Info("first")
+	logger.Info("second")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if n := strings.Count(string(data), "failed to load timezone"); n != 1 {
+		t.Errorf("expected one warning, got %d in %q", n, data)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if !strings.Contains(line, "Z level=") {
+			t.Errorf("expected the time in UTC, got %q", line)
+		}
+	}
+}
+
+func TestReplaceTimezone(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*60*60)
+	replace := replaceTimezone(&Config{Timezone: loc})
+
+	ts := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := replace(nil, slogTime(ts))
+	if got := a.Value.Time(); got.Location() != loc || !got.Equal(ts) {
+		t.Errorf("expected %v in %v, got %v", ts, loc, got)
+	}
+
+	// Time attributes inside groups are left alone
+	if got := replace([]string{"g"}, slogTime(ts)).Value.Time(); got.Location() != time.UTC {
+		t.Errorf("expected grouped time to be unchanged, got %v", got)
+	}
+}
+
+// slogTime returns a built-in time attribute with the given value.
+func slogTime(t time.Time) slog.Attr {
+	return slog.Time(slog.TimeKey, t)
+}
@@ -0,0 +1,59 @@
+package slog
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	attrTransformsMu sync.RWMutex
+	attrTransforms   = make(map[string][]func(slog.Value) slog.Value)
+)
+
+// RegisterAttrTransform registers a function that transforms the value of
+// every attribute with the given key, for example to hash an id or truncate
+// a field. Attributes inside groups are matched by their dotted path (for
+// example "req.id"). Transforms registered for the same key are applied in
+// order of registration.
+//
+// Transforms are applied by the loggers built after they are registered, so
+// register them before calling Init or Build.
+func RegisterAttrTransform(key string, fn func(slog.Value) slog.Value) {
+	attrTransformsMu.Lock()
+	defer attrTransformsMu.Unlock()
+	attrTransforms[key] = append(attrTransforms[key], fn)
+}
+
+// replaceAttrTransforms returns a ReplaceAttr function that applies the
+// registered attribute transforms, or nil if none are registered.
+func replaceAttrTransforms() func([]string, slog.Attr) slog.Attr {
+	attrTransformsMu.RLock()
+	defer attrTransformsMu.RUnlock()
+	if len(attrTransforms) == 0 {
+		return nil
+	}
+
+	transforms := cloneAttrTransforms(attrTransforms)
+
+	return func(groups []string, a slog.Attr) slog.Attr {
+		path := a.Key
+		if len(groups) > 0 {
+			path = strings.Join(groups, ".") + "." + a.Key
+		}
+		for _, fn := range transforms[path] {
+			a.Value = fn(a.Value)
+		}
+		return a
+	}
+}
+
+// cloneAttrTransforms returns a copy of the attribute transforms m.
+func cloneAttrTransforms(m map[string][]func(slog.Value) slog.Value) map[string][]func(slog.Value) slog.Value {
+	c := make(map[string][]func(slog.Value) slog.Value, len(m))
+	for key, fns := range m {
+		c[key] = slices.Clone(fns)
+	}
+	return c
+}
@@ -0,0 +1,63 @@
+package slog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// resetAttrTransforms removes all registered attribute transforms.
+func resetAttrTransforms() {
+	attrTransformsMu.Lock()
+	defer attrTransformsMu.Unlock()
+	attrTransforms = make(map[string][]func(slog.Value) slog.Value)
+}
+
+func TestRegisterAttrTransform(t *testing.T) {
+	defer resetAttrTransforms()
+
+	hash := func(v slog.Value) slog.Value {
+		sum := sha256.Sum256([]byte(v.String()))
+		return slog.StringValue(hex.EncodeToString(sum[:4]))
+	}
+	truncate := func(v slog.Value) slog.Value {
+		if s := v.String(); len(s) > 5 {
+			return slog.StringValue(s[:5] + "...")
+		}
+		return v
+	}
+	RegisterAttrTransform("user_id", hash)
+	RegisterAttrTransform("body", truncate)
+	RegisterAttrTransform("req.path", func(v slog.Value) slog.Value {
+		return slog.StringValue(strings.ToUpper(v.String()))
+	})
+	// Transforms for the same key are applied in order
+	RegisterAttrTransform("req.path", truncate)
+
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare"}, &buf))
+	logger.Info("test", "user_id", "u-1", "body", "hello world", "path", "/other",
+		slog.Group("req", "path", "/users/1"))
+
+	expected := "msg=test user_id=" + hash(slog.StringValue("u-1")).String() +
+		" body=hello... path=/other req.path=/USER...\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestRegisterAttrTransformAfterBuild(t *testing.T) {
+	defer resetAttrTransforms()
+
+	var buf bytes.Buffer
+	logger := slog.New(createHandler(&Config{HandlerType: "bare"}, &buf))
+	RegisterAttrTransform("k", func(slog.Value) slog.Value { return slog.StringValue("changed") })
+
+	logger.Info("test", "k", "v")
+	if expected := "msg=test k=v\n"; buf.String() != expected {
+		t.Errorf("expected transforms registered after build to be ignored, got %q", buf.String())
+	}
+}
@@ -0,0 +1,11 @@
+// Package tzdata embeds the time zone database for the planks-go/slog package.
+// Importing this package makes LOGGER_TIMEZONE work on systems without zoneinfo
+// files, such as scratch container images, at the cost of about 450 KB of
+// program size. It is equivalent to importing time/tzdata:
+//
+//	import _ "github.com/nakat-t/planks-go/slog/tzdata"
+package tzdata
+
+import (
+	_ "time/tzdata"
+)
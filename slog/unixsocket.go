@@ -0,0 +1,95 @@
+package slog
+
+import (
+	"net"
+	"sync"
+)
+
+// DefaultUnixSocketType is the socket type dialed for LOGGER_WRITER=unixsocket
+// unless LOGGER_UNIX_SOCKET_TYPE is set.
+const DefaultUnixSocketType = "unix"
+
+// unixSocketWriter is a writer that sends the records to a Unix domain
+// socket, for example that of a local log collector. It is a stream ("unix")
+// or datagram ("unixgram") socket; for datagram sockets each record is sent
+// as one datagram.
+//
+// If a write fails, for example because the collector restarted, the socket
+// is dialed again and the write retried once. For stream sockets only the part
+// of the record that was not written before the failure is retried, so that
+// its beginning is not sent twice. If the socket cannot be dialed, the write
+// fails, and the next write dials again. After Close, writes fail with
+// net.ErrClosed.
+type unixSocketWriter struct {
+	mu      sync.Mutex
+	network string
+	path    string
+	conn    net.Conn
+	closed  bool
+}
+
+// newUnixSocketWriter dials the Unix domain socket at path and returns a
+// writer to it. network is "unix" or "unixgram".
+func newUnixSocketWriter(network, path string) (*unixSocketWriter, error) {
+	conn, err := net.Dial(network, path)
+	if err != nil {
+		return nil, err
+	}
+	return &unixSocketWriter{network: network, path: path, conn: conn}, nil
+}
+
+// Write implements io.Writer.
+func (w *unixSocketWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, net.ErrClosed
+	}
+
+	written := 0
+	if w.conn != nil {
+		n, err := w.conn.Write(p)
+		if err == nil {
+			return n, nil
+		}
+		w.conn.Close()
+		w.conn = nil
+		// A datagram is sent whole or not at all, so it is resent whole.
+		if w.network == "unix" {
+			written = n
+		}
+	}
+
+	conn, err := net.Dial(w.network, w.path)
+	if err != nil {
+		return written, err
+	}
+	w.conn = conn
+	n, err := conn.Write(p[written:])
+	return written + n, err
+}
+
+// Close closes the connection to the socket.
+func (w *unixSocketWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// hasUnixSocketOutput reports whether any of the outputs writes to a Unix domain socket.
+func hasUnixSocketOutput(outputs []Output) bool {
+	for _, output := range outputs {
+		if output.WriterType == "unixsocket" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package slog
+
+// unixSocketSupported reports whether LOGGER_WRITER=unixsocket is available.
+// Unix domain sockets are only supported on Unix platforms.
+const unixSocketSupported = false
@@ -0,0 +1,202 @@
+//go:build unix
+
+package slog
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// unixSocketPath returns the path of a socket in a new temporary directory.
+// t.TempDir is not used, as its paths may exceed the length limit of socket paths.
+func unixSocketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "planks")
+	if err != nil {
+		t.Fatalf("failed to create directory: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "log.sock")
+}
+
+func TestUnixSocketWriter(t *testing.T) {
+	path := unixSocketPath(t)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	// The collector reads one line per connection and then closes it, so
+	// that the second record is only received after reconnecting.
+	lines := make(chan string)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				close(lines)
+				return
+			}
+			line, _ := bufio.NewReader(conn).ReadString('\n')
+			conn.Close()
+			lines <- line
+		}
+	}()
+
+	w, err := createWriter(&Config{WriterType: "unixsocket", UnixSocketType: "unix", UnixSocketPath: path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.(*unixSocketWriter).Close()
+
+	if _, err := w.Write([]byte("first\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if line := <-lines; line != "first\n" {
+		t.Errorf("expected first record, got %q", line)
+	}
+
+	// The collector closed the connection before sending the first
+	// record, so the write fails with EPIPE and the writer reconnects.
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if line := <-lines; line != "second\n" {
+		t.Errorf("expected second record after reconnecting, got %q", line)
+	}
+}
+
+// partialConn is a connection whose writes fail after writing n bytes.
+type partialConn struct {
+	net.Conn
+	n int
+}
+
+func (c *partialConn) Write(p []byte) (int, error) {
+	return c.n, errors.New("connection reset")
+}
+
+func (c *partialConn) Close() error {
+	return nil
+}
+
+func TestUnixSocketWriterPartialWrite(t *testing.T) {
+	path := unixSocketPath(t)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	lines := make(chan string)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			close(lines)
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		lines <- line
+	}()
+
+	// The old connection accepted 3 bytes before failing, so only the rest
+	// of the record is sent after reconnecting.
+	w := &unixSocketWriter{network: "unix", path: path, conn: &partialConn{n: 3}}
+	defer w.Close()
+	n, err := w.Write([]byte("record\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != len("record\n") {
+		t.Errorf("expected %d bytes written, got %d", len("record\n"), n)
+	}
+	if line := <-lines; line != "ord\n" {
+		t.Errorf("expected the unwritten rest of the record, got %q", line)
+	}
+}
+
+func TestUnixSocketWriterClosed(t *testing.T) {
+	path := unixSocketPath(t)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	w, err := newUnixSocketWriter("unix", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Writing after Close must not dial a new connection
+	if _, err := w.Write([]byte("late\n")); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("expected net.ErrClosed, got %v", err)
+	}
+	if w.conn != nil {
+		t.Errorf("expected no connection after Close")
+	}
+}
+
+func TestUnixSocketWriterDatagram(t *testing.T) {
+	path := unixSocketPath(t)
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	w, err := newUnixSocketWriter("unixgram", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Close()
+
+	for _, record := range []string{"first\n", "second\n"} {
+		if _, err := w.Write([]byte(record)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	buf := make([]byte, 64)
+	for _, expected := range []string{"first\n", "second\n"} {
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("failed to read: %v", err)
+		}
+		if string(buf[:n]) != expected {
+			t.Errorf("expected datagram %q, got %q", expected, buf[:n])
+		}
+	}
+}
+
+func TestReadConfigUnixSocket(t *testing.T) {
+	config, err := readConfigFromEnv(t, map[string]string{
+		EnvLoggerWriter:         "unixsocket",
+		EnvLoggerUnixSocketPath: "/run/collector.sock",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.UnixSocketPath != "/run/collector.sock" || config.UnixSocketType != DefaultUnixSocketType {
+		t.Errorf("unexpected socket settings: %q %q", config.UnixSocketPath, config.UnixSocketType)
+	}
+
+	if _, err := readConfigFromEnv(t, map[string]string{EnvLoggerWriter: "unixsocket"}); !errors.Is(err, ErrMissingUnixSocketPath) {
+		t.Errorf("expected ErrMissingUnixSocketPath, got %v", err)
+	}
+	_, err = readConfigFromEnv(t, map[string]string{
+		EnvLoggerWriter:         "unixsocket",
+		EnvLoggerUnixSocketPath: "/run/collector.sock",
+		EnvLoggerUnixSocketType: "tcp",
+	})
+	if !errors.Is(err, ErrInvalidUnixSocketType) {
+		t.Errorf("expected ErrInvalidUnixSocketType, got %v", err)
+	}
+}
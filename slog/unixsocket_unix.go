@@ -0,0 +1,6 @@
+//go:build unix
+
+package slog
+
+// unixSocketSupported reports whether LOGGER_WRITER=unixsocket is available.
+const unixSocketSupported = true
@@ -0,0 +1,51 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// uptimeHandler is a wrapper handler that adds the time since the process
+// started, as recorded when the package was initialized, to each record as an
+// "uptime" attribute, rounded to the millisecond like the heartbeat's.
+type uptimeHandler struct {
+	next slog.Handler
+}
+
+// newUptimeHandler creates a new handler that adds the process uptime to each record.
+func newUptimeHandler(next slog.Handler) slog.Handler {
+	return &uptimeHandler{next: next}
+}
+
+// Enabled implements slog.Handler.Enabled.
+func (h *uptimeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.Handle.
+func (h *uptimeHandler) Handle(ctx context.Context, r slog.Record) error {
+	r = r.Clone()
+	r.AddAttrs(slog.Duration("uptime", time.Since(processStart).Round(time.Millisecond)))
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.WithAttrs.
+func (h *uptimeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &uptimeHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.WithGroup.
+func (h *uptimeHandler) WithGroup(name string) slog.Handler {
+	return &uptimeHandler{next: h.next.WithGroup(name)}
+}
+
+// describe implements describer.
+func (h *uptimeHandler) describe() string {
+	return "uptime"
+}
+
+// unwrap implements describer.
+func (h *uptimeHandler) unwrap() slog.Handler {
+	return h.next
+}
@@ -0,0 +1,47 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUptimeHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(newUptimeHandler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.Info("first")
+	time.Sleep(5 * time.Millisecond)
+	logger.Info("second")
+
+	var uptimes []time.Duration
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record struct {
+			Uptime time.Duration `json:"uptime"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to parse %q: %v", line, err)
+		}
+		uptimes = append(uptimes, record.Uptime)
+	}
+	if len(uptimes) != 2 || uptimes[0] <= 0 || uptimes[1] <= uptimes[0] {
+		t.Errorf("expected increasing uptimes, got %v", uptimes)
+	}
+}
+
+func TestReadConfigAddUptime(t *testing.T) {
+	// Save original environment variables
+	origEnvs := saveEnvVars()
+	defer restoreEnvVars(origEnvs)
+
+	config, err := readConfigFromEnv(t, map[string]string{EnvLoggerAddUptime: "true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !config.AddUptime {
+		t.Error("expected AddUptime to be enabled")
+	}
+}
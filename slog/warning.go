@@ -0,0 +1,23 @@
+package slog
+
+import "log/slog"
+
+// configWarning is a problem found while reading the configuration that does
+// not prevent building the logger.
+type configWarning struct {
+	msg  string
+	args []any
+}
+
+// addWarning records a warning to be logged once the logger is built.
+func (c *Config) addWarning(msg string, args ...any) {
+	c.warnings = append(c.warnings, configWarning{msg: msg, args: args})
+}
+
+// emitWarnings logs the config's warnings through the newly built logger, so
+// that they reach the configured destination.
+func emitWarnings(config *Config, logger *slog.Logger) {
+	for _, w := range config.warnings {
+		logger.Warn(w.msg, w.args...)
+	}
+}